@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+)
+
+// abapGit repository layout
+const (
+	abapGitStartingFolder = "src"
+	abapGitRepoFile       = ".abapgit.xml"
+	abapGitPackageFile    = "package.devc.xml"
+	abapGitLanguage       = "E"
+)
+
+// abapGitClassIncludes maps abapGit class include file suffixes to ADT class include names
+var abapGitClassIncludes = map[string]string{
+	"locals_def":  types.ClassIncludeDefinitions,
+	"locals_imp":  types.ClassIncludeImplementations,
+	"testclasses": types.ClassIncludeTestClasses,
+	"macros":      types.ClassIncludeMacros,
+}
+
+// PullSummary reports the outcome of a package export
+type PullSummary struct {
+	Objects  int
+	Files    int
+	Skipped  []string
+	Failures []string
+}
+
+// abapGitName converts an object name to its abapGit file name form (lowercase, namespaces with #)
+func abapGitName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "/", "#"))
+}
+
+// abapGitFolder returns the folder name of a subpackage using abapGit's PREFIX folder logic
+func abapGitFolder(parentPackage, childPackage string) string {
+	prefix := parentPackage + "_"
+	if strings.HasPrefix(childPackage, prefix) && len(childPackage) > len(prefix) {
+		return abapGitName(strings.TrimPrefix(childPackage, prefix))
+	}
+	return abapGitName(childPackage)
+}
+
+// xmlText escapes a string for use in XML element content
+func xmlText(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// abapGitXML wraps serialized values in the abapGit object XML envelope
+func abapGitXML(serializer, values string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<abapGit version="v1.0.0" serializer="` + serializer + `" serializer_version="v1.0.0">
+ <asx:abap xmlns:asx="http://www.sap.com/abapxml" version="1.0">
+  <asx:values>
+` + values + `  </asx:values>
+ </asx:abap>
+</abapGit>
+`
+}
+
+// abapGitRepoXML returns the .abapgit.xml repository settings
+func abapGitRepoXML() string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<asx:abap xmlns:asx="http://www.sap.com/abapxml" version="1.0">
+ <asx:values>
+  <DATA>
+   <MASTER_LANGUAGE>` + abapGitLanguage + `</MASTER_LANGUAGE>
+   <STARTING_FOLDER>/` + abapGitStartingFolder + `/</STARTING_FOLDER>
+   <FOLDER_LOGIC>PREFIX</FOLDER_LOGIC>
+  </DATA>
+ </asx:values>
+</asx:abap>
+`
+}
+
+// writeAbapGitFile writes a file below dir and counts it in the summary
+func writeAbapGitFile(summary *PullSummary, dir, fileName, content string) error {
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	summary.Files++
+	return nil
+}
+
+// HandlePull exports a package and its subpackages into an abapGit layout
+func HandlePull(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectName == "" {
+		return fmt.Errorf("package name required: %s pull PACKAGE --out DIR", "abaper")
+	}
+
+	packageName := strings.ToUpper(config.ObjectName)
+	outDir := config.OutputPath
+	if outDir == "" {
+		outDir = abapGitName(packageName)
+	}
+
+	if !quiet || normal {
+		fmt.Printf("📦 Reading package tree of %s...\n", packageName)
+	}
+
+	tree, err := adtClient.GetPackageTree(packageName)
+	if err != nil {
+		return fmt.Errorf("failed to read package %s: %w", packageName, err)
+	}
+
+	srcDir := filepath.Join(outDir, abapGitStartingFolder)
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", srcDir, err)
+	}
+
+	summary := &PullSummary{}
+	if err := writeAbapGitFile(summary, outDir, abapGitRepoFile, abapGitRepoXML()); err != nil {
+		return err
+	}
+
+	if err := pullPackage(*tree, srcDir, adtClient, summary, quiet, normal); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Pull %s ===\n", packageName)
+	fmt.Printf("Directory: %s\n", outDir)
+	fmt.Printf("Objects: %d\n", summary.Objects)
+	fmt.Printf("Files: %d\n", summary.Files)
+	if len(summary.Skipped) > 0 {
+		fmt.Printf("Skipped (unsupported type): %d\n", len(summary.Skipped))
+		for _, name := range summary.Skipped {
+			fmt.Printf("  • %s\n", name)
+		}
+	}
+	if len(summary.Failures) > 0 {
+		fmt.Printf("Failed: %d\n", len(summary.Failures))
+		for _, failure := range summary.Failures {
+			fmt.Printf("  ❌ %s\n", failure)
+		}
+		return fmt.Errorf("%d object(s) could not be exported", len(summary.Failures))
+	}
+
+	fmt.Println("✅ Pull completed")
+	return nil
+}
+
+// pullPackage writes a package node and recurses into its subpackages
+func pullPackage(node types.ADTNode, dir string, adtClient types.ADTClient, summary *PullSummary, quiet bool, normal bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	devc := "   <DEVC>\n    <CTEXT>" + xmlText(node.Description) + "</CTEXT>\n   </DEVC>\n"
+	if err := writeAbapGitFile(summary, dir, abapGitPackageFile, abapGitXML("LCL_OBJECT_DEVC", devc)); err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		if child.Type == types.ObjectTypePackage {
+			if err := pullPackage(child, filepath.Join(dir, abapGitFolder(node.Name, child.Name)), adtClient, summary, quiet, normal); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !quiet || normal {
+			fmt.Printf("📄 %s %s\n", child.Type, child.Name)
+		}
+
+		handled, err := pullObject(child, dir, adtClient, summary)
+		if err != nil {
+			summary.Failures = append(summary.Failures, fmt.Sprintf("%s %s: %v", child.Type, child.Name, err))
+			continue
+		}
+		if !handled {
+			summary.Skipped = append(summary.Skipped, fmt.Sprintf("%s %s", child.Type, child.Name))
+			continue
+		}
+		summary.Objects++
+	}
+
+	return nil
+}
+
+// pullObject downloads a single object into dir. It returns false for unsupported object types.
+func pullObject(obj types.ADTNode, dir string, adtClient types.ADTClient, summary *PullSummary) (bool, error) {
+	base := abapGitName(obj.Name)
+
+	switch obj.Type {
+	case types.ObjectTypeProgram, types.ObjectTypeInclude:
+		var source *types.ADTSourceCode
+		var err error
+		subc := "1"
+		if obj.Type == types.ObjectTypeInclude {
+			source, err = adtClient.GetInclude(obj.Name)
+			subc = "I"
+		} else {
+			source, err = adtClient.GetProgram(obj.Name)
+		}
+		if err != nil {
+			return true, err
+		}
+		if err := writeAbapGitFile(summary, dir, base+".prog.abap", source.Source); err != nil {
+			return true, err
+		}
+		values := "   <PROGDIR>\n    <NAME>" + xmlText(obj.Name) + "</NAME>\n    <SUBC>" + subc + "</SUBC>\n    <FIXPT>X</FIXPT>\n    <UCCHECK>X</UCCHECK>\n   </PROGDIR>\n" +
+			"   <TPOOL>\n    <item>\n     <ID>R</ID>\n     <ENTRY>" + xmlText(obj.Description) + "</ENTRY>\n    </item>\n   </TPOOL>\n"
+		return true, writeAbapGitFile(summary, dir, base+".prog.xml", abapGitXML("LCL_OBJECT_PROG", values))
+
+	case types.ObjectTypeClass:
+		includes, err := adtClient.GetClassIncludes(obj.Name, nil)
+		if err != nil {
+			return true, err
+		}
+
+		withTests := ""
+		for _, include := range includes {
+			fileName := base + ".clas.abap"
+			if include.Include != types.ClassIncludeMain {
+				if strings.TrimSpace(include.Source.Source) == "" {
+					continue
+				}
+				for suffix, name := range abapGitClassIncludes {
+					if name == include.Include {
+						fileName = base + ".clas." + suffix + ".abap"
+					}
+				}
+			}
+			if include.Include == types.ClassIncludeTestClasses {
+				withTests = "    <WITH_UNIT_TESTS>X</WITH_UNIT_TESTS>\n"
+			}
+			if err := writeAbapGitFile(summary, dir, fileName, include.Source.Source); err != nil {
+				return true, err
+			}
+		}
+
+		values := "   <VSEOCLASS>\n    <CLSNAME>" + xmlText(obj.Name) + "</CLSNAME>\n    <LANGU>" + abapGitLanguage + "</LANGU>\n    <DESCRIPT>" + xmlText(obj.Description) + "</DESCRIPT>\n    <STATE>1</STATE>\n    <CLSCCINCL>X</CLSCCINCL>\n    <FIXPT>X</FIXPT>\n    <UNICODE>X</UNICODE>\n" + withTests + "   </VSEOCLASS>\n"
+		return true, writeAbapGitFile(summary, dir, base+".clas.xml", abapGitXML("LCL_OBJECT_CLAS", values))
+
+	case types.ObjectTypeInterface:
+		source, err := adtClient.GetInterface(obj.Name)
+		if err != nil {
+			return true, err
+		}
+		if err := writeAbapGitFile(summary, dir, base+".intf.abap", source.Source); err != nil {
+			return true, err
+		}
+		values := "   <VSEOINTERF>\n    <CLSNAME>" + xmlText(obj.Name) + "</CLSNAME>\n    <LANGU>" + abapGitLanguage + "</LANGU>\n    <DESCRIPT>" + xmlText(obj.Description) + "</DESCRIPT>\n    <EXPOSURE>2</EXPOSURE>\n    <STATE>1</STATE>\n    <UNICODE>X</UNICODE>\n   </VSEOINTERF>\n"
+		return true, writeAbapGitFile(summary, dir, base+".intf.xml", abapGitXML("LCL_OBJECT_INTF", values))
+
+	case types.ObjectTypeFunctionGroup:
+		return true, pullFunctionGroup(obj, dir, adtClient, summary)
+
+	default:
+		return false, nil
+	}
+}
+
+// pullFunctionGroup writes the main program, includes and function modules of a function group
+func pullFunctionGroup(obj types.ADTNode, dir string, adtClient types.ADTClient, summary *PullSummary) error {
+	base := abapGitName(obj.Name) + ".fugr."
+
+	main, err := adtClient.GetFunctionGroup(obj.Name)
+	if err != nil {
+		return err
+	}
+	mainProgram := "SAPL" + obj.Name
+	if err := writeAbapGitFile(summary, dir, base+abapGitName(mainProgram)+".abap", main.Source); err != nil {
+		return err
+	}
+
+	contents, err := adtClient.GetFunctionGroupContents(obj.Name)
+	if err != nil {
+		return err
+	}
+
+	var includes, functions strings.Builder
+	for _, item := range contents {
+		switch item.Type {
+		case types.ObjectTypeFunction:
+			source, err := adtClient.GetFunction(item.Name, obj.Name)
+			if err != nil {
+				return fmt.Errorf("function module %s: %w", item.Name, err)
+			}
+			if err := writeAbapGitFile(summary, dir, base+abapGitName(item.Name)+".abap", source.Source); err != nil {
+				return err
+			}
+			functions.WriteString("    <item>\n     <FUNCNAME>" + xmlText(item.Name) + "</FUNCNAME>\n     <SHORT_TEXT>" + xmlText(item.Description) + "</SHORT_TEXT>\n    </item>\n")
+
+		case types.ObjectTypeFunctionInc:
+			source, err := adtClient.GetSourceByURI(item.URI + "/source/main")
+			if err != nil {
+				return fmt.Errorf("include %s: %w", item.Name, err)
+			}
+			if err := writeAbapGitFile(summary, dir, base+abapGitName(item.Name)+".abap", source.Source); err != nil {
+				return err
+			}
+			includes.WriteString("    <SOBJ_NAME>" + xmlText(item.Name) + "</SOBJ_NAME>\n")
+		}
+	}
+	includes.WriteString("    <SOBJ_NAME>" + xmlText(mainProgram) + "</SOBJ_NAME>\n")
+
+	values := "   <AREAT>" + xmlText(obj.Description) + "</AREAT>\n" +
+		"   <INCLUDES>\n" + includes.String() + "   </INCLUDES>\n" +
+		"   <FUNCTIONS>\n" + functions.String() + "   </FUNCTIONS>\n"
+	return writeAbapGitFile(summary, dir, base+"xml", abapGitXML("LCL_OBJECT_FUGR", values))
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+)
+
+// Push actions reported per file
+const (
+	pushCreated   = "created"
+	pushUpdated   = "updated"
+	pushUnchanged = "unchanged"
+	pushFailed    = "failed"
+)
+
+// pushItem is a local source file mapped to its repository object
+type pushItem struct {
+	File          string          // path relative to the starting folder
+	Object        types.ADTObject // object that is locked and activated
+	SourceURI     string          // URI the source is written to
+	Package       string          // package derived from the folder
+	FunctionGroup string          // function group of a function module
+	Description   string          // description from the XML metadata file
+	Creatable     bool            // main source of an object that can be created if missing
+	Source        string
+	Action        string
+	Error         error
+	Activation    string
+}
+
+// abapGitXMLValue returns the text of the first element with the given name in an XML file
+func abapGitXMLValue(path, element string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == element {
+			var value string
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				return ""
+			}
+			return value
+		}
+	}
+}
+
+// abapGitFunctions returns the function modules of a function group XML file with their short texts
+func abapGitFunctions(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var file struct {
+		Functions []struct {
+			Name      string `xml:"FUNCNAME"`
+			ShortText string `xml:"SHORT_TEXT"`
+		} `xml:"abap>values>FUNCTIONS>item"`
+	}
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil
+	}
+
+	functions := make(map[string]string)
+	for _, function := range file.Functions {
+		functions[strings.ToUpper(function.Name)] = function.ShortText
+	}
+	return functions
+}
+
+// abapGitObjectName converts an abapGit file name part back to an object name
+func abapGitObjectName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "#", "/"))
+}
+
+// abapGitPackage derives the package of a folder, the inverse of abapGitFolder: a folder is
+// the parent package's name suffix of a subpackage, or the full name of a subpackage without
+// the parent prefix. The full name is used when only that package exists on the system.
+func abapGitPackage(rootPackage, relDir string, exists func(string) bool) string {
+	pkg := strings.ToUpper(rootPackage)
+	if relDir == "." || relDir == "" {
+		return pkg
+	}
+	for _, folder := range strings.Split(filepath.ToSlash(relDir), "/") {
+		name := abapGitObjectName(folder)
+		prefixed := pkg + "_" + name
+		if !exists(prefixed) && exists(name) {
+			pkg = name
+		} else {
+			pkg = prefixed
+		}
+	}
+	return pkg
+}
+
+// normalizeSource makes sources comparable regardless of line endings and trailing newlines
+func normalizeSource(source string) string {
+	return strings.TrimRight(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+}
+
+// parseAbapGitFile maps a .abap file to a push item. It returns nil for unsupported files.
+func parseAbapGitFile(dir, fileName string) (*pushItem, error) {
+	parts := strings.Split(strings.TrimSuffix(fileName, ".abap"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, nil
+	}
+
+	name := abapGitObjectName(parts[0])
+	kind := parts[1]
+	extra := ""
+	if len(parts) == 3 {
+		extra = parts[2]
+	}
+	metadata := filepath.Join(dir, parts[0]+"."+kind+".xml")
+
+	item := &pushItem{}
+	switch {
+	case kind == "prog" && extra == "":
+		objectType := types.ObjectTypeProgram
+		if abapGitXMLValue(metadata, "SUBC") == "I" {
+			objectType = types.ObjectTypeInclude
+		}
+		obj, err := types.ObjectReference(objectType, name, "")
+		if err != nil {
+			return nil, err
+		}
+		item.Object = obj
+		item.SourceURI = obj.URI + "/source/main"
+		item.Description = abapGitXMLValue(metadata, "ENTRY")
+		item.Creatable = true
+
+	case kind == "clas":
+		obj, err := types.ObjectReference(types.ObjectTypeClass, name, "")
+		if err != nil {
+			return nil, err
+		}
+		item.Object = obj
+		item.SourceURI = obj.URI + "/source/main"
+		item.Description = abapGitXMLValue(metadata, "DESCRIPT")
+		item.Creatable = extra == ""
+		if extra != "" {
+			include, ok := abapGitClassIncludes[extra]
+			if !ok {
+				return nil, nil
+			}
+			item.SourceURI = obj.URI + "/includes/" + include
+		}
+
+	case kind == "intf" && extra == "":
+		obj, err := types.ObjectReference(types.ObjectTypeInterface, name, "")
+		if err != nil {
+			return nil, err
+		}
+		item.Object = obj
+		item.SourceURI = obj.URI + "/source/main"
+		item.Description = abapGitXMLValue(metadata, "DESCRIPT")
+		item.Creatable = true
+
+	case kind == "fugr" && extra != "":
+		program := abapGitObjectName(extra)
+		shortText, isFunction := abapGitFunctions(metadata)[program]
+
+		var obj types.ADTObject
+		var err error
+		switch {
+		case program == "SAPL"+name:
+			obj, err = types.ObjectReference(types.ObjectTypeFunctionGroup, name, "")
+			item.Description = abapGitXMLValue(metadata, "AREAT")
+			item.Creatable = true
+		case isFunction:
+			obj, err = types.ObjectReference(types.ObjectTypeFunction, program, name)
+			item.FunctionGroup = name
+			item.Description = shortText
+			item.Creatable = true
+		default:
+			obj = types.ADTObject{
+				Name: program,
+				Type: types.ObjectTypeFunctionInc,
+				URI:  "/sap/bc/adt/functions/groups/" + name + "/includes/" + program,
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		item.Object = obj
+		item.SourceURI = obj.URI + "/source/main"
+
+	default:
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+// collectPushItems walks the starting folder and maps every supported source file
+func collectPushItems(srcDir, rootPackage string, packageExists func(string) bool) ([]*pushItem, []string, error) {
+	var items []*pushItem
+	var skipped []string
+
+	err := filepath.WalkDir(srcDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".abap") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		item, err := parseAbapGitFile(filepath.Dir(path), entry.Name())
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if item == nil {
+			skipped = append(skipped, rel)
+			return nil
+		}
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		item.File = rel
+		item.Source = string(source)
+		item.Package = abapGitPackage(rootPackage, filepath.Dir(rel), packageExists)
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Main sources first so objects exist before their includes are written, and function
+	// groups before their function modules
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].createOrder() < items[j].createOrder()
+	})
+
+	return items, skipped, nil
+}
+
+// createOrder ranks items by when they have to be pushed: objects, then function modules,
+// then includes and other parts of existing objects
+func (item *pushItem) createOrder() int {
+	switch {
+	case !item.Creatable:
+		return 2
+	case item.FunctionGroup != "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// pushFile compares a local file with the server version and writes it if it differs
+func pushFile(item *pushItem, transport string, adtClient types.ADTClient) {
+	current, err := adtClient.GetSourceByURI(item.SourceURI)
+	if isNotFound(err) && item.Creatable {
+		if err := adtClient.CreateObject(item.Object.Type, item.Object.Name, types.CreateOptions{
+			Description:   item.Description,
+			Package:       item.Package,
+			FunctionGroup: item.FunctionGroup,
+			Transport:     transport,
+		}); err != nil {
+			item.Action, item.Error = pushFailed, err
+			return
+		}
+		item.Action = pushCreated
+		current, err = adtClient.GetSourceByURI(item.SourceURI)
+	}
+	if err != nil {
+		item.Action, item.Error = pushFailed, err
+		return
+	}
+
+	if normalizeSource(current.Source) == normalizeSource(item.Source) {
+		if item.Action == "" {
+			item.Action = pushUnchanged
+		}
+		return
+	}
+
+	current.Source = item.Source
+	if _, err := adtClient.UpdateSource(current, transport); err != nil {
+		item.Action, item.Error = pushFailed, err
+		return
+	}
+	if item.Action == "" {
+		item.Action = pushUpdated
+	}
+}
+
+// HandlePush writes a local abapGit directory back to the system and activates the changed objects
+func HandlePush(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.FilePath == "" {
+		return fmt.Errorf("directory required: %s push DIR --package PACKAGE", "abaper")
+	}
+	if config.Package == "" {
+		return fmt.Errorf("package required: %s push DIR --package PACKAGE", "abaper")
+	}
+
+	rootPackage := strings.ToUpper(config.Package)
+	srcDir := config.FilePath
+	if _, err := os.Stat(filepath.Join(srcDir, abapGitRepoFile)); err == nil {
+		folder := strings.Trim(abapGitXMLValue(filepath.Join(srcDir, abapGitRepoFile), "STARTING_FOLDER"), "/")
+		if folder == "" {
+			folder = abapGitStartingFolder
+		}
+		srcDir = filepath.Join(srcDir, folder)
+	}
+
+	// Folders are mapped to packages with a lookup of the candidates on the system
+	known := make(map[string]bool)
+	packageExists := func(name string) bool {
+		exists, ok := known[name]
+		if !ok {
+			_, err := adtClient.GetPackageInfo(name)
+			exists = err == nil
+			known[name] = exists
+		}
+		return exists
+	}
+
+	items, skipped, err := collectPushItems(srcDir, rootPackage, packageExists)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no ABAP sources found in %s", srcDir)
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🚀 Pushing %d file(s) from %s to package %s...\n", len(items), srcDir, rootPackage)
+	}
+
+	var changed []types.ADTObject
+	seen := make(map[string]bool)
+	for _, item := range items {
+		pushFile(item, config.Transport, adtClient)
+
+		if !quiet || normal {
+			fmt.Printf("📄 %s: %s\n", item.File, item.Action)
+		}
+		if (item.Action == pushCreated || item.Action == pushUpdated) && !seen[item.Object.URI] {
+			seen[item.Object.URI] = true
+			changed = append(changed, item.Object)
+		}
+	}
+
+	var activation *types.ADTActivationResult
+	if len(changed) > 0 {
+		if !quiet || normal {
+			fmt.Printf("⚡ Activating %d object(s)...\n", len(changed))
+		}
+		activation, err = adtClient.Activate(changed)
+		if err != nil {
+			return fmt.Errorf("activation failed: %w", err)
+		}
+		for _, item := range items {
+			if !seen[item.Object.URI] {
+				continue
+			}
+			item.Activation = "active"
+			for _, msg := range activation.Messages {
+				if msg.Severity == "error" && (msg.URI == "" || strings.HasPrefix(msg.URI, item.Object.URI)) {
+					item.Activation = "activation failed"
+					break
+				}
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	fmt.Printf("\n=== Push %s ===\n", rootPackage)
+	for _, item := range items {
+		counts[item.Action]++
+		icon := "✅"
+		switch {
+		case item.Action == pushFailed || item.Activation == "activation failed":
+			icon = "❌"
+		case item.Action == pushUnchanged:
+			icon = "➖"
+		}
+		fmt.Printf("%s %s %s (%s): %s", icon, item.Object.Type, item.Object.Name, item.File, item.Action)
+		if item.Activation != "" {
+			fmt.Printf(", %s", item.Activation)
+		}
+		if item.Error != nil {
+			fmt.Printf(" - %v", item.Error)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\nCreated: %d, Updated: %d, Unchanged: %d, Failed: %d\n",
+		counts[pushCreated], counts[pushUpdated], counts[pushUnchanged], counts[pushFailed])
+
+	if len(skipped) > 0 {
+		fmt.Printf("Skipped (unsupported file): %d\n", len(skipped))
+		for _, file := range skipped {
+			fmt.Printf("  • %s\n", file)
+		}
+	}
+
+	if activation != nil && (len(activation.Messages) > 0 || len(activation.Inactive) > 0) {
+		fmt.Println()
+		printActivationResult(activation)
+	}
+
+	if counts[pushFailed] > 0 {
+		return fmt.Errorf("%d file(s) could not be pushed", counts[pushFailed])
+	}
+	if activation != nil && !activation.Success {
+		return fmt.Errorf("activation failed")
+	}
+
+	fmt.Println("✅ Push completed")
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bluefunda/abaper/types"
+)
+
+func TestAbapGitPackageRoundTrip(t *testing.T) {
+	// Package chains from the root package down, as pull walks them
+	tests := []struct {
+		name  string
+		chain []string
+	}{
+		{"root", []string{"ZSALES"}},
+		{"prefixed subpackage", []string{"ZSALES", "ZSALES_UI"}},
+		{"nested prefixed subpackages", []string{"ZSALES", "ZSALES_UI", "ZSALES_UI_CORE"}},
+		{"subpackage without prefix", []string{"ZSALES", "ZFINANCE"}},
+		{"prefixed below unprefixed", []string{"ZSALES", "ZFINANCE", "ZFINANCE_TAX"}},
+		{"unprefixed below prefixed", []string{"ZSALES", "ZSALES_UI", "ZWIDGETS"}},
+		{"namespace", []string{"/ABC/SALES", "/ABC/SALES_UI", "/ABC/TOOLS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists := map[string]bool{}
+			relDir := "."
+			for i, pkg := range tt.chain {
+				exists[pkg] = true
+				if i > 0 {
+					relDir = filepath.Join(relDir, abapGitFolder(tt.chain[i-1], pkg))
+				}
+			}
+
+			want := tt.chain[len(tt.chain)-1]
+			got := abapGitPackage(tt.chain[0], relDir, func(name string) bool { return exists[name] })
+			if got != want {
+				t.Errorf("abapGitPackage(%s, %s) = %s, want %s", tt.chain[0], relDir, got, want)
+			}
+		})
+	}
+}
+
+func TestAbapGitPackageNewFolder(t *testing.T) {
+	// Folders of packages that do not exist yet follow the prefix logic
+	got := abapGitPackage("zsales", "ui/core", func(string) bool { return false })
+	if got != "ZSALES_UI_CORE" {
+		t.Errorf("abapGitPackage() = %s, want ZSALES_UI_CORE", got)
+	}
+}
+
+func TestCollectPushItemsFunctionGroup(t *testing.T) {
+	dir := t.TempDir()
+	values := "   <AREAT>Tax calculation</AREAT>\n" +
+		"   <FUNCTIONS>\n" +
+		"    <item>\n     <FUNCNAME>Z_CALCULATE_TAX</FUNCNAME>\n     <SHORT_TEXT>Calculate tax</SHORT_TEXT>\n    </item>\n" +
+		"   </FUNCTIONS>\n"
+	files := map[string]string{
+		"ztax.fugr.xml":                  abapGitXML("LCL_OBJECT_FUGR", values),
+		"ztax.fugr.lztaxtop.abap":        "FUNCTION-POOL ztax.\n",
+		"ztax.fugr.z_calculate_tax.abap": "FUNCTION z_calculate_tax.\nENDFUNCTION.\n",
+		"ztax.fugr.saplztax.abap":        "INCLUDE lztaxtop.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items, skipped, err := collectPushItems(dir, "ZSALES", func(string) bool { return false })
+	if err != nil {
+		t.Fatalf("collectPushItems() failed: %v", err)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want none", skipped)
+	}
+
+	want := []struct {
+		objectType    string
+		name          string
+		creatable     bool
+		functionGroup string
+		description   string
+	}{
+		{types.ObjectTypeFunctionGroup, "ZTAX", true, "", "Tax calculation"},
+		{types.ObjectTypeFunction, "Z_CALCULATE_TAX", true, "ZTAX", "Calculate tax"},
+		{types.ObjectTypeFunctionInc, "LZTAXTOP", false, "", ""},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, w := range want {
+		item := items[i]
+		if item.Object.Type != w.objectType || item.Object.Name != w.name {
+			t.Errorf("item %d = %s %s, want %s %s", i, item.Object.Type, item.Object.Name, w.objectType, w.name)
+		}
+		if item.Creatable != w.creatable || item.FunctionGroup != w.functionGroup || item.Description != w.description {
+			t.Errorf("item %d %s: creatable %v, group %q, description %q, want %v, %q, %q",
+				i, item.Object.Name, item.Creatable, item.FunctionGroup, item.Description, w.creatable, w.functionGroup, w.description)
+		}
+		if item.Package != "ZSALES" {
+			t.Errorf("item %d package = %s, want ZSALES", i, item.Package)
+		}
+	}
+}
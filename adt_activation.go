@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtActivationMessages maps the chkl:messages document returned by an activation
+type adtActivationMessages struct {
+	XMLName  xml.Name `xml:"messages"`
+	Messages []struct {
+		ObjDescr  string `xml:"objDescr,attr"`
+		Type      string `xml:"type,attr"`
+		Line      string `xml:"line,attr"`
+		Href      string `xml:"href,attr"`
+		ShortText struct {
+			Txt []string `xml:"txt"`
+		} `xml:"shortText"`
+	} `xml:"msg"`
+}
+
+// adtInactiveObjects maps the ioc:inactiveObjects document
+type adtInactiveObjects struct {
+	XMLName xml.Name `xml:"inactiveObjects"`
+	Entries []struct {
+		Object *struct {
+			User    string       `xml:"user,attr"`
+			Deleted string       `xml:"deleted,attr"`
+			Ref     adtObjectRef `xml:"ref"`
+		} `xml:"object"`
+		Transport *struct {
+			Ref adtObjectRef `xml:"ref"`
+		} `xml:"transport"`
+	} `xml:"entry"`
+}
+
+// Activate activates the given objects and returns the activation messages
+func (c *ADTClientImpl) Activate(objects []types.ADTObject) (*types.ADTActivationResult, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects to activate")
+	}
+
+	c.logger.Info("Activating objects", zap.Int("count", len(objects)))
+
+	var payload bytes.Buffer
+	payload.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	payload.WriteString(`<adtcore:objectReferences xmlns:adtcore="http://www.sap.com/adt/core">`)
+	for _, obj := range objects {
+		if obj.URI == "" {
+			return nil, fmt.Errorf("object %s has no URI", obj.Name)
+		}
+		payload.WriteString(`<adtcore:objectReference adtcore:uri="`)
+		xml.EscapeText(&payload, []byte(obj.URI))
+		payload.WriteString(`" adtcore:name="`)
+		xml.EscapeText(&payload, []byte(obj.Name))
+		payload.WriteString(`"/>`)
+	}
+	payload.WriteString(`</adtcore:objectReferences>`)
+
+	activationURL := c.baseURL + ADT_ACTIVATION_ENDPOINT + "?method=activate&preauditRequested=true"
+	resp, body, err := c.doRequest("POST", activationURL, &payload, map[string]string{
+		"Content-Type": "application/xml",
+		"Accept":       "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return nil, fmt.Errorf("activation failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	result, err := parseActivationResult(body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Activation completed",
+		zap.Bool("success", result.Success),
+		zap.Int("messages", len(result.Messages)),
+		zap.Int("inactive", len(result.Inactive)))
+
+	return result, nil
+}
+
+// parseActivationResult parses an activation response body into a typed result
+func parseActivationResult(body []byte) (*types.ADTActivationResult, error) {
+	result := &types.ADTActivationResult{
+		Success:  true,
+		Messages: []types.ADTActivationMessage{},
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return result, nil
+	}
+
+	switch xmlRootName(body) {
+	case "messages":
+		var messages adtActivationMessages
+		if err := xml.Unmarshal(body, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse activation messages: %w", err)
+		}
+		for _, msg := range messages.Messages {
+			message := types.ADTActivationMessage{
+				ObjectDescription: msg.ObjDescr,
+				Type:              msg.Type,
+				Severity:          messageSeverity(msg.Type),
+				Text:              strings.Join(msg.ShortText.Txt, " "),
+			}
+			message.URI, message.Line, message.Column = parseSourcePosition(msg.Href)
+			if line, err := strconv.Atoi(msg.Line); err == nil && message.Line == 0 {
+				message.Line = line
+			}
+			if message.Severity == "error" {
+				result.Success = false
+			}
+			result.Messages = append(result.Messages, message)
+		}
+
+	case "inactiveObjects":
+		// The system asks for dependent inactive objects to be activated together
+		var inactive adtInactiveObjects
+		if err := xml.Unmarshal(body, &inactive); err != nil {
+			return nil, fmt.Errorf("failed to parse inactive objects: %w", err)
+		}
+		for _, entry := range inactive.Entries {
+			if entry.Object != nil {
+				result.Inactive = append(result.Inactive, entry.Object.Ref.toADTObject())
+			}
+		}
+		result.Success = false
+	}
+
+	return result, nil
+}
+
+// messageSeverity maps ABAP message types to a severity name
+func messageSeverity(messageType string) string {
+	switch strings.ToUpper(messageType) {
+	case "E", "A", "X":
+		return "error"
+	case "W":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// parseSourcePosition splits an ADT href like .../source/main#start=12,3 into URI, line and column
+func parseSourcePosition(href string) (string, int, int) {
+	uri, fragment, found := strings.Cut(href, "#")
+	if !found {
+		return href, 0, 0
+	}
+
+	for _, part := range strings.Split(fragment, ";") {
+		if value, ok := strings.CutPrefix(part, "start="); ok {
+			lineStr, colStr, _ := strings.Cut(value, ",")
+			line, _ := strconv.Atoi(lineStr)
+			column, _ := strconv.Atoi(colStr)
+			return uri, line, column
+		}
+	}
+
+	return uri, 0, 0
+}
+
+// GetInactiveObjects lists the inactive objects of the current user
+func (c *ADTClientImpl) GetInactiveObjects() ([]types.ADTInactiveObject, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Retrieving inactive objects")
+
+	resp, body, err := c.doRequest("GET", c.baseURL+ADT_INACTIVE_OBJECTS_ENDPOINT, nil, map[string]string{
+		"Accept": "application/vnd.sap.adt.inactivectsobjects.v1+xml, application/xml;q=0.8",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get inactive objects: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	objects := []types.ADTInactiveObject{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return objects, nil
+	}
+
+	var inactive adtInactiveObjects
+	if err := xml.Unmarshal(body, &inactive); err != nil {
+		return nil, fmt.Errorf("failed to parse inactive objects: %w", err)
+	}
+
+	for _, entry := range inactive.Entries {
+		if entry.Object == nil {
+			continue
+		}
+		item := types.ADTInactiveObject{
+			Object:  entry.Object.Ref.toADTObject(),
+			User:    entry.Object.User,
+			Deleted: entry.Object.Deleted == "true",
+		}
+		if entry.Transport != nil {
+			item.Transport = entry.Transport.Ref.Name
+		}
+		objects = append(objects, item)
+	}
+
+	c.logger.Info("Inactive objects retrieved successfully", zap.Int("count", len(objects)))
+
+	return objects, nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// ATC polling configuration
+const (
+	atcPollInterval = 2 * time.Second
+	atcPollTimeout  = 10 * time.Minute
+)
+
+// adtATCCustomizing maps the atcinfo:customizing document
+type adtATCCustomizing struct {
+	XMLName    xml.Name `xml:"customizing"`
+	Properties []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"properties>property"`
+}
+
+// adtATCWorklistRun maps the atcworklist:worklistRun document returned by a run
+type adtATCWorklistRun struct {
+	XMLName   xml.Name `xml:"worklistRun"`
+	ID        string   `xml:"worklistId"`
+	Timestamp string   `xml:"worklistTimestamp"`
+}
+
+// adtATCWorklist maps the atcworklist:worklist document
+type adtATCWorklist struct {
+	XMLName             xml.Name `xml:"worklist"`
+	ID                  string   `xml:"id,attr"`
+	Timestamp           string   `xml:"timestamp,attr"`
+	ObjectSetIsComplete string   `xml:"objectSetIsComplete,attr"`
+	Objects             []struct {
+		URI         string `xml:"uri,attr"`
+		Type        string `xml:"type,attr"`
+		Name        string `xml:"name,attr"`
+		PackageName string `xml:"packageName,attr"`
+		Findings    []struct {
+			Location     string `xml:"location,attr"`
+			Priority     string `xml:"priority,attr"`
+			CheckID      string `xml:"checkId,attr"`
+			CheckTitle   string `xml:"checkTitle,attr"`
+			MessageID    string `xml:"messageId,attr"`
+			MessageTitle string `xml:"messageTitle,attr"`
+		} `xml:"findings>finding"`
+	} `xml:"objects>object"`
+}
+
+// RunATC creates an ATC worklist for the given variant, runs it for the objects
+// and waits for the worklist to complete. An empty variant uses the system default.
+func (c *ADTClientImpl) RunATC(objects []types.ADTObject, variant string, maxVerdicts int) (*types.ADTATCResult, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects to check")
+	}
+	if maxVerdicts <= 0 {
+		maxVerdicts = 100
+	}
+
+	if variant == "" {
+		defaultVariant, err := c.getATCDefaultVariant()
+		if err != nil {
+			return nil, err
+		}
+		variant = defaultVariant
+	}
+	variant = strings.ToUpper(variant)
+
+	c.logger.Info("Starting ATC run",
+		zap.String("variant", variant),
+		zap.Int("objects", len(objects)),
+		zap.Int("max_verdicts", maxVerdicts))
+
+	// Step 1: Create worklist for the check variant
+	worklistURL := fmt.Sprintf("%s%s?checkVariant=%s", c.baseURL, ADT_ATC_WORKLISTS_ENDPOINT, url.QueryEscape(variant))
+	resp, body, err := c.doRequest("POST", worklistURL, nil, map[string]string{"Accept": "text/plain"})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create ATC worklist: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+	worklistID := strings.TrimSpace(string(body))
+	if worklistID == "" {
+		return nil, fmt.Errorf("ATC worklist creation returned no worklist ID")
+	}
+
+	// Step 2: Start the run for the object set
+	var payload bytes.Buffer
+	payload.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(&payload, `<atc:run maximumVerdicts="%d" xmlns:atc="http://www.sap.com/adt/atc">`, maxVerdicts)
+	payload.WriteString(`<objectSets xmlns:adtcore="http://www.sap.com/adt/core"><objectSet kind="inclusive"><adtcore:objectReferences>`)
+	for _, obj := range objects {
+		payload.WriteString(`<adtcore:objectReference adtcore:uri="`)
+		xml.EscapeText(&payload, []byte(obj.URI))
+		payload.WriteString(`"/>`)
+	}
+	payload.WriteString(`</adtcore:objectReferences></objectSet></objectSets></atc:run>`)
+
+	runURL := fmt.Sprintf("%s%s?worklistId=%s", c.baseURL, ADT_ATC_RUNS_ENDPOINT, url.QueryEscape(worklistID))
+	resp, body, err = c.doRequest("POST", runURL, &payload, map[string]string{
+		"Content-Type": "application/xml",
+		"Accept":       "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to start ATC run: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var run adtATCWorklistRun
+	if err := xml.Unmarshal(body, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse ATC run response: %w", err)
+	}
+
+	// Step 3: Poll the worklist until the object set is complete
+	worklist, err := c.waitForATCWorklist(worklistID, run.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.ADTATCResult{
+		WorklistID: worklistID,
+		Variant:    variant,
+		Timestamp:  worklist.Timestamp,
+		Findings:   []types.ADTATCFinding{},
+	}
+
+	for _, obj := range worklist.Objects {
+		for _, f := range obj.Findings {
+			finding := types.ADTATCFinding{
+				ObjectURI:    obj.URI,
+				ObjectName:   obj.Name,
+				ObjectType:   obj.Type,
+				Package:      obj.PackageName,
+				CheckID:      f.CheckID,
+				CheckTitle:   f.CheckTitle,
+				MessageID:    f.MessageID,
+				MessageTitle: f.MessageTitle,
+			}
+			finding.Priority, _ = strconv.Atoi(f.Priority)
+			finding.URI, finding.Line, finding.Column = parseSourcePosition(f.Location)
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+
+	c.logger.Info("ATC run completed",
+		zap.String("worklist_id", worklistID),
+		zap.Int("findings", len(result.Findings)))
+
+	return result, nil
+}
+
+// waitForATCWorklist polls the worklist until its object set is complete
+func (c *ADTClientImpl) waitForATCWorklist(worklistID, timestamp string) (*adtATCWorklist, error) {
+	query := url.Values{
+		"usedObjectSet":           {"99999999999999999999999999999999"},
+		"includeExemptedFindings": {"false"},
+	}
+	if timestamp != "" {
+		query.Set("timestamp", timestamp)
+	}
+	worklistURL := fmt.Sprintf("%s%s/%s?%s", c.baseURL, ADT_ATC_WORKLISTS_ENDPOINT, url.PathEscape(worklistID), query.Encode())
+
+	deadline := time.Now().Add(atcPollTimeout)
+	for {
+		resp, body, err := c.doRequest("GET", worklistURL, nil, map[string]string{
+			"Accept": "application/atc.worklist.v1+xml",
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to get ATC worklist: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+		}
+
+		var worklist adtATCWorklist
+		if err := xml.Unmarshal(body, &worklist); err != nil {
+			return nil, fmt.Errorf("failed to parse ATC worklist: %w", err)
+		}
+
+		if worklist.ObjectSetIsComplete != "false" {
+			return &worklist, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("ATC worklist %s did not complete within %s", worklistID, atcPollTimeout)
+		}
+
+		c.logger.Debug("ATC worklist not complete yet, polling again", zap.String("worklist_id", worklistID))
+		time.Sleep(atcPollInterval)
+	}
+}
+
+// getATCDefaultVariant reads the system check variant from the ATC customizing
+func (c *ADTClientImpl) getATCDefaultVariant() (string, error) {
+	resp, body, err := c.doRequest("GET", c.baseURL+ADT_ATC_CUSTOMIZING_ENDPOINT, nil, map[string]string{
+		"Accept": "application/xml",
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to read ATC customizing: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var customizing adtATCCustomizing
+	if err := xml.Unmarshal(body, &customizing); err != nil {
+		return "", fmt.Errorf("failed to parse ATC customizing: %w", err)
+	}
+
+	for _, property := range customizing.Properties {
+		if property.Name == "systemCheckVariant" && property.Value != "" {
+			return property.Value, nil
+		}
+	}
+
+	return "", fmt.Errorf("no default ATC check variant configured - use --variant")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtCheckReport maps a chkrun:checkReport element
+type adtCheckReport struct {
+	Reporter   string `xml:"reporter,attr"`
+	Status     string `xml:"status,attr"`
+	StatusText string `xml:"statusText,attr"`
+	Messages   []struct {
+		URI       string `xml:"uri,attr"`
+		Type      string `xml:"type,attr"`
+		ShortText string `xml:"shortText,attr"`
+		Category  string `xml:"category,attr"`
+		Code      string `xml:"code,attr"`
+	} `xml:"checkMessageList>checkMessage"`
+}
+
+// adtCheckRunReports maps the chkrun:checkRunReports document
+type adtCheckRunReports struct {
+	XMLName xml.Name         `xml:"checkRunReports"`
+	Reports []adtCheckReport `xml:"checkReport"`
+}
+
+// SyntaxCheck runs the ADT syntax check for an object. If source is not empty
+// it is checked instead of the version stored on the server; pass a source URI
+// (e.g. a class include) to check a source other than the main source.
+func (c *ADTClientImpl) SyntaxCheck(objectURI, source string) (*types.ADTSyntaxCheckResult, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if objectURI == "" {
+		return nil, fmt.Errorf("object URI required for syntax check")
+	}
+
+	c.logger.Info("Running syntax check",
+		zap.String("uri", objectURI),
+		zap.Bool("local_source", source != ""))
+
+	var payload bytes.Buffer
+	payload.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	payload.WriteString(`<chkrun:checkObjectList xmlns:adtcore="http://www.sap.com/adt/core" xmlns:chkrun="http://www.sap.com/adt/checkrun">`)
+	payload.WriteString(`<chkrun:checkObject adtcore:uri="`)
+	xml.EscapeText(&payload, []byte(adtObjectURI(objectURI)))
+	payload.WriteString(`" chkrun:version="inactive">`)
+	if source != "" {
+		payload.WriteString(`<chkrun:artifacts><chkrun:artifact chkrun:contentType="text/plain; charset=utf-8" chkrun:uri="`)
+		xml.EscapeText(&payload, []byte(sourceURIOf(objectURI)))
+		payload.WriteString(`"><chkrun:content>`)
+		payload.WriteString(base64.StdEncoding.EncodeToString([]byte(source)))
+		payload.WriteString(`</chkrun:content></chkrun:artifact></chkrun:artifacts>`)
+	}
+	payload.WriteString(`</chkrun:checkObject></chkrun:checkObjectList>`)
+
+	checkURL := c.baseURL + ADT_CHECKRUNS_ENDPOINT + "?reporters=abapCheckRun"
+	resp, body, err := c.doRequest("POST", checkURL, &payload, map[string]string{
+		"Content-Type": "application/vnd.sap.adt.checkobjects+xml",
+		"Accept":       "application/vnd.sap.adt.checkmessages+xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "object %s not found (404)", objectURI)
+		}
+		return nil, fmt.Errorf("syntax check failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var reports adtCheckRunReports
+	if err := xml.Unmarshal(body, &reports); err != nil {
+		return nil, fmt.Errorf("failed to parse syntax check response: %w", err)
+	}
+
+	result := &types.ADTSyntaxCheckResult{
+		ObjectURI: objectURI,
+		Messages:  []types.ADTSyntaxMessage{},
+	}
+
+	for _, report := range reports.Reports {
+		if result.Status == "" {
+			result.Status = report.Status
+		}
+		for _, msg := range report.Messages {
+			message := types.ADTSyntaxMessage{
+				Type:     msg.Type,
+				Severity: messageSeverity(msg.Type),
+				Text:     msg.ShortText,
+				Category: msg.Category,
+				Code:     msg.Code,
+			}
+			message.URI, message.Line, message.Column = parseSourcePosition(msg.URI)
+			if message.Severity == "error" {
+				result.HasErrors = true
+			}
+			result.Messages = append(result.Messages, message)
+		}
+	}
+
+	c.logger.Info("Syntax check completed",
+		zap.String("uri", objectURI),
+		zap.Bool("has_errors", result.HasErrors),
+		zap.Int("messages", len(result.Messages)))
+
+	return result, nil
+}
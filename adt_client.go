@@ -1,16 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bluefunda/abaper/types"
@@ -19,20 +22,43 @@ import (
 
 // ADT Endpoint Constants
 const (
-	ADT_PROGRAMS_ENDPOINT         = "/programs/programs/%s/source/main"
-	ADT_CLASSES_ENDPOINT          = "/oo/classes/%s/source/main"
-	ADT_FUNCTION_GROUPS_ENDPOINT  = "/functions/groups/%s/source/main"
-	ADT_FUNCTIONS_ENDPOINT        = "/functions/groups/%s/fmodules/%s/source/main"
-	ADT_TABLES_ENDPOINT           = "/ddic/tables/%s/source/main"
-	ADT_STRUCTURES_ENDPOINT       = "/ddic/structures/%s/source/main"
-	ADT_INCLUDES_ENDPOINT         = "/programs/includes/%s/source/main"
-	ADT_INTERFACES_ENDPOINT       = "/oo/interfaces/%s/source/main"
-	ADT_DOMAINS_ENDPOINT          = "/ddic/domains/%s/source/main"
-	ADT_DATA_ELEMENTS_ENDPOINT    = "/ddic/dataelements/%s"
-	ADT_PACKAGE_CONTENTS_ENDPOINT = "/repository/nodestructure"
-	ADT_SEARCH_ENDPOINT           = "/repository/informationsystem/search"
-	ADT_TRANSACTION_ENDPOINT      = "/repository/informationsystem/objectproperties/values"
-	ADT_TABLE_CONTENTS_ENDPOINT   = "/z_mcp_abap_adt/z_tablecontent/%s" // Custom service required
+	ADT_PROGRAMS_ENDPOINT                = "/programs/programs/%s/source/main"
+	ADT_CLASSES_ENDPOINT                 = "/oo/classes/%s/source/main"
+	ADT_FUNCTION_GROUPS_ENDPOINT         = "/functions/groups/%s/source/main"
+	ADT_FUNCTIONS_ENDPOINT               = "/functions/groups/%s/fmodules/%s/source/main"
+	ADT_TABLES_ENDPOINT                  = "/ddic/tables/%s/source/main"
+	ADT_STRUCTURES_ENDPOINT              = "/ddic/structures/%s/source/main"
+	ADT_INCLUDES_ENDPOINT                = "/programs/includes/%s/source/main"
+	ADT_INTERFACES_ENDPOINT              = "/oo/interfaces/%s/source/main"
+	ADT_DOMAINS_ENDPOINT                 = "/ddic/domains/%s/source/main"
+	ADT_DATA_ELEMENTS_ENDPOINT           = "/ddic/dataelements/%s"
+	ADT_PACKAGE_CONTENTS_ENDPOINT        = "/repository/nodestructure"
+	ADT_SEARCH_ENDPOINT                  = "/repository/informationsystem/search"
+	ADT_TRANSACTION_ENDPOINT             = "/repository/informationsystem/objectproperties/values"
+	ADT_TABLE_CONTENTS_ENDPOINT          = "/z_mcp_abap_adt/z_tablecontent/%s" // Custom service required
+	ADT_ACTIVATION_ENDPOINT              = "/activation"
+	ADT_INACTIVE_OBJECTS_ENDPOINT        = "/activation/inactiveobjects"
+	ADT_CHECKRUNS_ENDPOINT               = "/checkruns"
+	ADT_ATC_CUSTOMIZING_ENDPOINT         = "/atc/customizing"
+	ADT_ATC_WORKLISTS_ENDPOINT           = "/atc/worklists"
+	ADT_ATC_RUNS_ENDPOINT                = "/atc/runs"
+	ADT_UNIT_TESTRUNS_ENDPOINT           = "/abapunit/testruns"
+	ADT_USAGE_REFERENCES_ENDPOINT        = "/repository/informationsystem/usageReferences"
+	ADT_USAGE_SNIPPETS_ENDPOINT          = "/repository/informationsystem/usageSnippets"
+	ADT_NAVIGATION_TARGET_ENDPOINT       = "/navigation/target"
+	ADT_COMPLETION_PROPOSAL_ENDPOINT     = "/abapsource/codecompletion/proposal"
+	ADT_COMPLETION_INSERTION_ENDPOINT    = "/abapsource/codecompletion/insertion"
+	ADT_PRETTY_PRINTER_ENDPOINT          = "/abapsource/prettyprinter"
+	ADT_PRETTY_PRINTER_SETTINGS_ENDPOINT = "/abapsource/prettyprinter/settings"
+	ADT_TRANSPORT_REQUESTS_ENDPOINT      = "/cts/transportrequests"
+	ADT_TRANSPORTS_ENDPOINT              = "/cts/transports"
+	ADT_TRANSPORT_CHECKS_ENDPOINT        = "/cts/transportchecks"
+)
+
+// ADT session header values
+const (
+	ADT_SESSION_TYPE_HEADER = "X-sap-adt-sessiontype"
+	ADT_LOCK_ACCEPT         = "application/*,application/vnd.sap.as+xml;charset=UTF-8;dataname=com.sap.adt.lock.result"
 )
 
 // ADTClientImpl implements the ADTClient interface using shared types
@@ -45,6 +71,13 @@ type ADTClientImpl struct {
 	baseURL       string
 	authenticated bool
 	sessionType   string // "stateful" or "stateless"
+	jar           *sessionJar
+	authMu        sync.Mutex // serializes logons after a session expired
+
+	// passwordSource supplies the password when a restored session has to log on again
+	passwordSource func() (string, error)
+	// sessionSaved is called after every successful authentication
+	sessionSaved func(*adtSession)
 }
 
 // NewADTClient creates a new ADT client with improved configuration
@@ -66,18 +99,27 @@ func NewADTClient(config *types.ADTConfig) types.ADTClient {
 	// Normalize and validate the host URL
 	baseURL := normalizeBaseURL(config.Host)
 
-	// Create cookie jar for session management
-	jar, err := cookiejar.New(nil)
-	if err != nil {
-		// Fallback to client without cookie jar
-		jar = nil
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.AllowSelfSigned,
+	}
+
+	// Trust additional certificates, e.g. of a company CA
+	if config.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if pem, err := os.ReadFile(config.CAFile); err != nil {
+			logger.Warn("Failed to read CA file", zap.String("ca_file", config.CAFile), zap.Error(err))
+		} else if !pool.AppendCertsFromPEM(pem) {
+			logger.Warn("CA file contains no PEM certificates", zap.String("ca_file", config.CAFile))
+		}
+		tlsConfig.RootCAs = pool
 	}
 
 	// Create HTTP client with proper configuration
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: config.AllowSelfSigned,
-		},
+		TLSClientConfig:    tlsConfig,
 		MaxIdleConns:       10,
 		IdleConnTimeout:    90 * time.Second,
 		DisableCompression: false,
@@ -87,11 +129,15 @@ func NewADTClient(config *types.ADTConfig) types.ADTClient {
 	client := &http.Client{
 		Timeout:   time.Duration(config.RequestTimeout) * time.Second,
 		Transport: transport,
-		Jar:       jar,
 	}
 
+	// Cookie jar for session management
+	jar := newSessionJar()
+	client.Jar = jar
+
 	adtClient := &ADTClientImpl{
 		config:      config,
+		jar:         jar,
 		httpClient:  client,
 		logger:      logger.With(zap.String("component", "adt_client")),
 		baseURL:     baseURL,
@@ -133,6 +179,14 @@ func (c *ADTClientImpl) SetSessionType(sessionType types.SessionType) {
 
 // Authenticate performs comprehensive authentication with SAP system
 func (c *ADTClientImpl) Authenticate() error {
+	if c.config.Password == "" && c.passwordSource != nil {
+		password, err := c.passwordSource()
+		if err != nil {
+			return err
+		}
+		c.config.Password = password
+	}
+
 	c.logger.Info("Starting SAP ADT authentication",
 		zap.String("host", c.config.Host),
 		zap.String("username", c.config.Username),
@@ -164,6 +218,10 @@ func (c *ADTClientImpl) Authenticate() error {
 		zap.String("csrf_token_length", fmt.Sprintf("%d", len(c.csrfToken))),
 		zap.String("session_type", c.sessionType))
 
+	if c.sessionSaved != nil {
+		c.sessionSaved(c.session())
+	}
+
 	return nil
 }
 
@@ -191,7 +249,7 @@ func (c *ADTClientImpl) GetProgram(programName string) (*types.ADTSourceCode, er
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -201,7 +259,7 @@ func (c *ADTClientImpl) GetProgram(programName string) (*types.ADTSourceCode, er
 		body, _ := io.ReadAll(resp.Body)
 
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("program %s not found (404)", programName)
+			return nil, statusError(http.StatusNotFound, "program %s not found (404)", programName)
 		} else if resp.StatusCode == http.StatusUnauthorized {
 			return nil, fmt.Errorf("authentication failed (401) - session may have expired")
 		} else if resp.StatusCode == http.StatusForbidden {
@@ -222,6 +280,7 @@ func (c *ADTClientImpl) GetProgram(programName string) (*types.ADTSourceCode, er
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Program retrieved successfully",
@@ -250,7 +309,7 @@ func (c *ADTClientImpl) GetClass(className string) (*types.ADTSourceCode, error)
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -260,7 +319,7 @@ func (c *ADTClientImpl) GetClass(className string) (*types.ADTSourceCode, error)
 		body, _ := io.ReadAll(resp.Body)
 
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("class %s not found (404)", className)
+			return nil, statusError(http.StatusNotFound, "class %s not found (404)", className)
 		} else if resp.StatusCode == http.StatusUnauthorized {
 			return nil, fmt.Errorf("authentication failed (401) - session may have expired")
 		} else if resp.StatusCode == http.StatusForbidden {
@@ -281,6 +340,7 @@ func (c *ADTClientImpl) GetClass(className string) (*types.ADTSourceCode, error)
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Class retrieved successfully",
@@ -290,6 +350,50 @@ func (c *ADTClientImpl) GetClass(className string) (*types.ADTSourceCode, error)
 	return result, nil
 }
 
+// GetClassIncludes retrieves the given class includes (all if none are given), each with its own ETag.
+// Local includes that do not exist for the class are omitted.
+func (c *ADTClientImpl) GetClassIncludes(className string, includes []string) ([]types.ADTClassInclude, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	className = strings.ToUpper(strings.TrimSpace(className))
+	if len(includes) == 0 {
+		includes = types.ClassIncludes
+	}
+
+	c.logger.Info("Retrieving class includes",
+		zap.String("class", className),
+		zap.Strings("includes", includes))
+
+	var result []types.ADTClassInclude
+	for _, include := range includes {
+		include = strings.ToLower(strings.TrimSpace(include))
+		sourceURI, err := types.ClassIncludeURI(className, include)
+		if err != nil {
+			return nil, err
+		}
+
+		source, err := c.GetSourceByURI(sourceURI)
+		if err != nil {
+			if include != types.ClassIncludeMain && isNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s include of class %s: %w", include, className, err)
+		}
+
+		source.ObjectName = className
+		source.ObjectType = "CLAS"
+		result = append(result, types.ADTClassInclude{Include: include, Source: source})
+	}
+
+	c.logger.Info("Class includes retrieved successfully",
+		zap.String("class", className),
+		zap.Int("include_count", len(result)))
+
+	return result, nil
+}
+
 // GetFunction retrieves ABAP function module source code
 func (c *ADTClientImpl) GetFunction(functionName, functionGroup string) (*types.ADTSourceCode, error) {
 	if !c.IsAuthenticated() {
@@ -312,7 +416,7 @@ func (c *ADTClientImpl) GetFunction(functionName, functionGroup string) (*types.
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -321,7 +425,7 @@ func (c *ADTClientImpl) GetFunction(functionName, functionGroup string) (*types.
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("function %s in group %s not found (404)", functionName, functionGroup)
+			return nil, statusError(http.StatusNotFound, "function %s in group %s not found (404)", functionName, functionGroup)
 		}
 		return nil, fmt.Errorf("failed to get function %s: HTTP %d - %s", functionName, resp.StatusCode, string(body))
 	}
@@ -337,6 +441,7 @@ func (c *ADTClientImpl) GetFunction(functionName, functionGroup string) (*types.
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Function module retrieved successfully",
@@ -366,7 +471,7 @@ func (c *ADTClientImpl) GetFunctionGroup(functionGroup string) (*types.ADTSource
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -375,7 +480,7 @@ func (c *ADTClientImpl) GetFunctionGroup(functionGroup string) (*types.ADTSource
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("function group %s not found (404)", functionGroup)
+			return nil, statusError(http.StatusNotFound, "function group %s not found (404)", functionGroup)
 		}
 		return nil, fmt.Errorf("failed to get function group %s: HTTP %d - %s", functionGroup, resp.StatusCode, string(body))
 	}
@@ -391,6 +496,7 @@ func (c *ADTClientImpl) GetFunctionGroup(functionGroup string) (*types.ADTSource
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Function group retrieved successfully",
@@ -419,7 +525,7 @@ func (c *ADTClientImpl) GetInclude(includeName string) (*types.ADTSourceCode, er
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -428,7 +534,7 @@ func (c *ADTClientImpl) GetInclude(includeName string) (*types.ADTSourceCode, er
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("include %s not found (404)", includeName)
+			return nil, statusError(http.StatusNotFound, "include %s not found (404)", includeName)
 		}
 		return nil, fmt.Errorf("failed to get include %s: HTTP %d - %s", includeName, resp.StatusCode, string(body))
 	}
@@ -444,6 +550,7 @@ func (c *ADTClientImpl) GetInclude(includeName string) (*types.ADTSourceCode, er
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Include retrieved successfully",
@@ -472,7 +579,7 @@ func (c *ADTClientImpl) GetInterface(interfaceName string) (*types.ADTSourceCode
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -481,7 +588,7 @@ func (c *ADTClientImpl) GetInterface(interfaceName string) (*types.ADTSourceCode
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("interface %s not found (404)", interfaceName)
+			return nil, statusError(http.StatusNotFound, "interface %s not found (404)", interfaceName)
 		}
 		return nil, fmt.Errorf("failed to get interface %s: HTTP %d - %s", interfaceName, resp.StatusCode, string(body))
 	}
@@ -497,6 +604,7 @@ func (c *ADTClientImpl) GetInterface(interfaceName string) (*types.ADTSourceCode
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Interface retrieved successfully",
@@ -525,7 +633,7 @@ func (c *ADTClientImpl) GetStructure(structureName string) (*types.ADTSourceCode
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -534,7 +642,7 @@ func (c *ADTClientImpl) GetStructure(structureName string) (*types.ADTSourceCode
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("structure %s not found (404)", structureName)
+			return nil, statusError(http.StatusNotFound, "structure %s not found (404)", structureName)
 		}
 		return nil, fmt.Errorf("failed to get structure %s: HTTP %d - %s", structureName, resp.StatusCode, string(body))
 	}
@@ -550,6 +658,7 @@ func (c *ADTClientImpl) GetStructure(structureName string) (*types.ADTSourceCode
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Structure retrieved successfully",
@@ -578,7 +687,7 @@ func (c *ADTClientImpl) GetTable(tableName string) (*types.ADTSourceCode, error)
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -587,7 +696,7 @@ func (c *ADTClientImpl) GetTable(tableName string) (*types.ADTSourceCode, error)
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("table %s not found (404)", tableName)
+			return nil, statusError(http.StatusNotFound, "table %s not found (404)", tableName)
 		}
 		return nil, fmt.Errorf("failed to get table %s: HTTP %d - %s", tableName, resp.StatusCode, string(body))
 	}
@@ -603,6 +712,7 @@ func (c *ADTClientImpl) GetTable(tableName string) (*types.ADTSourceCode, error)
 		Source:     string(source),
 		Version:    resp.Header.Get("ETag"),
 		ETag:       resp.Header.Get("ETag"),
+		URI:        adtObjectURI(url),
 	}
 
 	c.logger.Info("Table retrieved successfully",
@@ -612,6 +722,35 @@ func (c *ADTClientImpl) GetTable(tableName string) (*types.ADTSourceCode, error)
 	return result, nil
 }
 
+// adtNodeStructure maps the asx:abap document returned by the repository node structure service
+type adtNodeStructure struct {
+	XMLName xml.Name `xml:"abap"`
+	Nodes   []struct {
+		ObjectType  string `xml:"OBJECT_TYPE"`
+		ObjectName  string `xml:"OBJECT_NAME"`
+		TechName    string `xml:"TECH_NAME"`
+		ObjectURI   string `xml:"OBJECT_URI"`
+		Description string `xml:"DESCRIPTION"`
+		Expandable  string `xml:"EXPANDABLE"`
+	} `xml:"values>DATA>TREE_CONTENT>SEU_ADT_REPOSITORY_OBJ_NODE"`
+}
+
+// adtPackageInfo maps the pak:package document
+type adtPackageInfo struct {
+	XMLName      xml.Name `xml:"package"`
+	Name         string   `xml:"name,attr"`
+	Description  string   `xml:"description,attr"`
+	SuperPackage struct {
+		Name string `xml:"name,attr"`
+	} `xml:"superPackage"`
+	ApplicationComponent struct {
+		Name string `xml:"name,attr"`
+	} `xml:"applicationComponent"`
+	SoftwareComponent struct {
+		Name string `xml:"name,attr"`
+	} `xml:"transport>softwareComponent"`
+}
+
 // GetPackageContents retrieves package contents
 func (c *ADTClientImpl) GetPackageContents(packageName string) (*types.ADTPackage, error) {
 	if !c.IsAuthenticated() {
@@ -622,106 +761,362 @@ func (c *ADTClientImpl) GetPackageContents(packageName string) (*types.ADTPackag
 
 	packageName = strings.ToUpper(strings.TrimSpace(packageName))
 
-	// Prepare POST data for package contents request
-	postData := url.Values{
-		"parent_type":           {"DEVC/K"},
-		"parent_name":           {packageName},
-		"withShortDescriptions": {"true"},
+	objects, err := c.getNodeStructure(types.ObjectTypePackage, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.ADTPackage{
+		Name:        packageName,
+		Description: c.getPackageDescription(packageName),
+		Objects:     []types.ADTObject{},
+	}
+
+	for _, obj := range objects {
+		obj.Package = packageName
+		if obj.Type == types.ObjectTypePackage {
+			if obj.Name == packageName {
+				continue
+			}
+			result.Subpackages = append(result.Subpackages, obj.Name)
+		}
+		result.Objects = append(result.Objects, obj)
+	}
+
+	c.logger.Info("Package contents retrieved successfully",
+		zap.String("package", packageName),
+		zap.Int("objects", len(result.Objects)),
+		zap.Int("subpackages", len(result.Subpackages)))
+
+	return result, nil
+}
+
+// GetFunctionGroupContents lists the function modules and includes of a function group
+func (c *ADTClientImpl) GetFunctionGroupContents(functionGroup string) ([]types.ADTObject, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+ADT_PACKAGE_CONTENTS_ENDPOINT, strings.NewReader(postData.Encode()))
+	c.logger.Info("Retrieving function group contents", zap.String("function_group", functionGroup))
+
+	functionGroup = strings.ToUpper(strings.TrimSpace(functionGroup))
+	objects, err := c.getNodeStructure(types.ObjectTypeFunctionGroup, functionGroup)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	c.addAuthHeaders(req)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Accept", "application/xml")
+	c.logger.Info("Function group contents retrieved successfully",
+		zap.String("function_group", functionGroup),
+		zap.Int("objects", len(objects)))
 
-	resp, err := c.httpClient.Do(req)
+	return objects, nil
+}
+
+// getNodeStructure reads the repository node structure below a parent object
+func (c *ADTClientImpl) getNodeStructure(parentType, parentName string) ([]types.ADTObject, error) {
+	// The node structure service expects its parameters in the query string
+	query := url.Values{
+		"parent_type":           {parentType},
+		"parent_name":           {parentName},
+		"withShortDescriptions": {"true"},
+	}
+
+	resp, responseBody, err := c.doRequest("POST", c.baseURL+ADT_PACKAGE_CONTENTS_ENDPOINT+"?"+query.Encode(), nil, map[string]string{
+		"Accept": "application/vnd.sap.as+xml, application/xml;q=0.8",
+	})
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("package %s not found (404)", packageName)
+			return nil, statusError(http.StatusNotFound, "%s %s not found (404)", parentType, parentName)
 		}
-		return nil, fmt.Errorf("failed to get package %s: HTTP %d - %s", packageName, resp.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to get contents of %s %s: HTTP %d - %s", parentType, parentName, resp.StatusCode, adtErrorMessage(responseBody))
 	}
 
-	responseBody, err := io.ReadAll(resp.Body)
+	objects := []types.ADTObject{}
+	if len(bytes.TrimSpace(responseBody)) == 0 {
+		return objects, nil
+	}
+
+	var nodes adtNodeStructure
+	if err := xml.Unmarshal(responseBody, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse node structure: %w", err)
+	}
+
+	for _, node := range nodes.Nodes {
+		// Category and type group nodes carry no object name
+		if node.ObjectName == "" || node.ObjectType == "" {
+			continue
+		}
+		objects = append(objects, types.ADTObject{
+			Name:        node.ObjectName,
+			Type:        node.ObjectType,
+			Description: node.Description,
+			URI:         node.ObjectURI,
+		})
+	}
+
+	return objects, nil
+}
+
+// GetSourceByURI retrieves source code from an ADT source URI (e.g. .../source/main or .../includes/testclasses)
+func (c *ADTClientImpl) GetSourceByURI(sourceURI string) (*types.ADTSourceCode, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Retrieving source", zap.String("uri", sourceURI))
+
+	resp, body, err := c.doRequest("GET", c.absoluteURL(sourceURI), nil, map[string]string{
+		"Accept": "text/plain",
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	// Parse XML response (simplified - would need proper XML parsing in production)
-	result := &types.ADTPackage{
-		Name:        packageName,
-		Description: fmt.Sprintf("Package %s", packageName),
-		Objects:     []types.ADTObject{}, // Would parse from XML response
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "source %s not found (404)", sourceURI)
+		}
+		return nil, fmt.Errorf("failed to get source %s: HTTP %d - %s", sourceURI, resp.StatusCode, adtErrorMessage(body))
 	}
 
-	c.logger.Info("Package contents retrieved successfully",
-		zap.String("package", packageName),
-		zap.Int("response_length", len(responseBody)))
+	objectURI := adtObjectURI(sourceURI)
+	result := &types.ADTSourceCode{
+		ObjectName: strings.ToUpper(objectURI[strings.LastIndex(objectURI, "/")+1:]),
+		Source:     string(body),
+		Version:    resp.Header.Get("ETag"),
+		ETag:       resp.Header.Get("ETag"),
+		URI:        objectURI,
+	}
+	if !strings.HasSuffix(sourceURI, "/source/main") {
+		result.SourceURI = sourceURI
+	}
+
+	c.logger.Info("Source retrieved successfully",
+		zap.String("uri", sourceURI),
+		zap.Int("source_length", len(result.Source)))
 
 	return result, nil
 }
 
+// getPackageInfo reads the package properties
+func (c *ADTClientImpl) getPackageInfo(packageName string) (*adtPackageInfo, error) {
+	packageURL := c.baseURL + "/packages/" + url.PathEscape(strings.ToLower(packageName))
+	resp, body, err := c.doRequest("GET", packageURL, nil, map[string]string{
+		"Accept": "application/vnd.sap.adt.packages.v1+xml, application/xml;q=0.8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get package %s: HTTP %d - %s", packageName, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var info adtPackageInfo
+	if err := xml.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse package %s: %w", packageName, err)
+	}
+	return &info, nil
+}
+
+// GetPackageInfo reads the properties of a package: description, super package and the
+// software and application component
+func (c *ADTClientImpl) GetPackageInfo(packageName string) (*types.ADTPackage, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	packageName = strings.ToUpper(strings.TrimSpace(packageName))
+	info, err := c.getPackageInfo(packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ADTPackage{
+		Name:                 packageName,
+		Description:          info.Description,
+		URI:                  "/sap/bc/adt/packages/" + url.PathEscape(strings.ToLower(packageName)),
+		ParentPackage:        info.SuperPackage.Name,
+		SoftwareComponent:    info.SoftwareComponent.Name,
+		ApplicationComponent: info.ApplicationComponent.Name,
+	}, nil
+}
+
+// getPackageDescription reads the package description, returning an empty string on failure
+func (c *ADTClientImpl) getPackageDescription(packageName string) string {
+	info, err := c.getPackageInfo(packageName)
+	if err != nil {
+		return ""
+	}
+	return info.Description
+}
+
+// GetPackageTree walks a package and all of its subpackages into a node tree
+func (c *ADTClientImpl) GetPackageTree(packageName string) (*types.ADTNode, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Retrieving package tree", zap.String("package", packageName))
+
+	visited := make(map[string]bool)
+	return c.buildPackageNode(strings.ToUpper(strings.TrimSpace(packageName)), visited)
+}
+
+// buildPackageNode recursively builds the node for a package
+func (c *ADTClientImpl) buildPackageNode(packageName string, visited map[string]bool) (*types.ADTNode, error) {
+	visited[packageName] = true
+
+	contents, err := c.GetPackageContents(packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &types.ADTNode{
+		Name:        contents.Name,
+		Type:        types.ObjectTypePackage,
+		Description: contents.Description,
+	}
+
+	for _, obj := range contents.Objects {
+		if obj.Type == types.ObjectTypePackage {
+			if visited[obj.Name] {
+				continue
+			}
+			child, err := c.buildPackageNode(obj.Name, visited)
+			if err != nil {
+				return nil, fmt.Errorf("failed to walk subpackage %s: %w", obj.Name, err)
+			}
+			child.URI = obj.URI
+			if child.Description == "" {
+				child.Description = obj.Description
+			}
+			node.Children = append(node.Children, *child)
+			continue
+		}
+
+		node.Children = append(node.Children, types.ADTNode{
+			Name:        obj.Name,
+			Type:        obj.Type,
+			Description: obj.Description,
+			URI:         obj.URI,
+		})
+	}
+
+	return node, nil
+}
+
 // SearchObjects searches for ABAP objects
 func (c *ADTClientImpl) SearchObjects(pattern string, objectTypes []string) (*types.ADTSearchResult, error) {
+	return c.SearchObjectsWithOptions(pattern, types.SearchOptions{ObjectTypes: objectTypes})
+}
+
+// SearchObjectsWithOptions searches for ABAP objects with type filters and paging.
+// The quick search has no offset parameter, so pages are cut from the first
+// Offset+MaxResults results of each object type.
+func (c *ADTClientImpl) SearchObjectsWithOptions(pattern string, options types.SearchOptions) (*types.ADTSearchResult, error) {
 	if !c.IsAuthenticated() {
 		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
 	}
 
+	if options.MaxResults <= 0 {
+		options.MaxResults = 100
+	}
+	if options.Offset < 0 {
+		options.Offset = 0
+	}
+
 	c.logger.Info("Searching objects",
 		zap.String("pattern", pattern),
-		zap.Strings("types", objectTypes))
+		zap.Strings("types", options.ObjectTypes),
+		zap.Int("max_results", options.MaxResults),
+		zap.Int("offset", options.Offset))
+
+	// One request per type filter; an empty filter searches all types
+	typeCodes := []string{""}
+	if len(options.ObjectTypes) > 0 {
+		typeCodes = typeCodes[:0]
+		for _, objectType := range options.ObjectTypes {
+			code, err := types.ObjectTypeCode(objectType)
+			if err != nil {
+				return nil, err
+			}
+			typeCodes = append(typeCodes, code)
+		}
+	}
 
-	maxResults := 100
-	searchURL := fmt.Sprintf("%s%s?operation=quickSearch&query=%s&maxResults=%d",
-		c.baseURL,
-		ADT_SEARCH_ENDPOINT,
-		url.QueryEscape(pattern),
-		maxResults)
+	fetchLimit := options.Offset + options.MaxResults
+	var objects []types.ADTObject
+	hasMore := false
 
-	req, err := http.NewRequest("GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	for _, typeCode := range typeCodes {
+		query := url.Values{
+			"operation":  {"quickSearch"},
+			"query":      {pattern},
+			"maxResults": {fmt.Sprintf("%d", fetchLimit)},
+		}
+		if typeCode != "" {
+			query.Set("objectType", typeCode)
+		}
 
-	c.addAuthHeaders(req)
-	req.Header.Set("Accept", "application/xml")
+		resp, responseBody, err := c.doRequest("GET", c.baseURL+ADT_SEARCH_ENDPOINT+"?"+query.Encode(), nil, map[string]string{
+			"Accept": "application/xml",
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("search failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(responseBody))
+		}
+
+		var references adtObjectReferences
+		if err := xml.Unmarshal(responseBody, &references); err != nil {
+			return nil, fmt.Errorf("failed to parse search response: %w", err)
+		}
+
+		if len(references.References) >= fetchLimit {
+			hasMore = true
+		}
+		for _, ref := range references.References {
+			objects = append(objects, ref.toADTObject())
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("search failed: HTTP %d - %s", resp.StatusCode, string(body))
+	// quickSearch does not report the number of hits, it is only known when no request
+	// reached its limit
+	result := &types.ADTSearchResult{
+		Objects: []types.ADTObject{},
+		Offset:  options.Offset,
+		HasMore: hasMore,
+	}
+	if !hasMore {
+		result.Total = len(objects)
 	}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	if options.Offset > 0 && options.Offset >= len(objects) {
+		return nil, fmt.Errorf("offset %d is beyond the last result (%d found)", options.Offset, len(objects))
 	}
 
-	var result types.ADTSearchResult
-	if err := xml.Unmarshal(responseBody, &result); err != nil {
-		log.Fatal(err)
+	if options.Offset < len(objects) {
+		end := options.Offset + options.MaxResults
+		if end < len(objects) {
+			result.HasMore = true
+		} else {
+			end = len(objects)
+		}
+		result.Objects = objects[options.Offset:end]
 	}
 
 	c.logger.Info("Search completed successfully",
 		zap.String("pattern", pattern),
-		zap.Int("response_length", len(responseBody)))
+		zap.Int("total", result.Total),
+		zap.Int("returned", len(result.Objects)))
 
-	return &result, nil
+	return result, nil
 }
 
 // ListPackages lists packages matching a pattern
@@ -751,7 +1146,7 @@ func (c *ADTClientImpl) ListPackages(pattern string) ([]types.ADTPackage, error)
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "application/xml")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -767,19 +1162,24 @@ func (c *ADTClientImpl) ListPackages(pattern string) ([]types.ADTPackage, error)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Parse XML response (simplified - would need proper XML parsing in production)
-	// For now, return some sample packages based on common SAP package patterns
-	packages := []types.ADTPackage{
-		{Name: "$TMP", Description: "Temporary Objects"},
-		{Name: "ZLOCAL", Description: "Local Development Package"},
+	var references adtObjectReferences
+	if err := xml.Unmarshal(responseBody, &references); err != nil {
+		return nil, fmt.Errorf("failed to parse package search response: %w", err)
 	}
 
-	// If pattern is specific, try to return a match
-	if pattern != "*" && !strings.Contains(pattern, "*") {
-		// Direct package name lookup
-		packages = []types.ADTPackage{
-			{Name: strings.ToUpper(pattern), Description: fmt.Sprintf("Package %s", strings.ToUpper(pattern))},
+	packages := []types.ADTPackage{}
+	for _, ref := range references.References {
+		if ref.Type != "" && ref.Type != types.ObjectTypePackage {
+			continue
 		}
+
+		// Component assignments are only available from the package itself, see GetPackageInfo
+		packages = append(packages, types.ADTPackage{
+			Name:          ref.Name,
+			Description:   ref.Description,
+			URI:           ref.URI,
+			ParentPackage: ref.PackageName,
+		})
 	}
 
 	c.logger.Info("Package search completed",
@@ -866,7 +1266,7 @@ func (c *ADTClientImpl) GetTransaction(transactionName string) (*types.ADTTransa
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "application/xml")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -875,7 +1275,7 @@ func (c *ADTClientImpl) GetTransaction(transactionName string) (*types.ADTTransa
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("transaction %s not found (404)", transactionName)
+			return nil, statusError(http.StatusNotFound, "transaction %s not found (404)", transactionName)
 		}
 		return nil, fmt.Errorf("failed to get transaction %s: HTTP %d - %s", transactionName, resp.StatusCode, string(body))
 	}
@@ -927,7 +1327,7 @@ func (c *ADTClientImpl) GetTableContents(tableName string, maxRows int) (*types.
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -958,31 +1358,6 @@ func (c *ADTClientImpl) GetTableContents(tableName string, maxRows int) (*types.
 	return &result, nil
 }
 
-func (c *ADTClientImpl) GetTransports() ([]types.ADTTransport, error) {
-	if !c.IsAuthenticated() {
-		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
-	}
-
-	c.logger.Info("Retrieving transport requests")
-
-	// This would require custom implementation in SAP system
-	// For now, return empty slice as this is an optional feature
-	return []types.ADTTransport{}, nil
-}
-
-// CreateProgram creates a new ABAP program
-func (c *ADTClientImpl) CreateProgram(name, description, source string) error {
-	if !c.IsAuthenticated() {
-		return fmt.Errorf("client not authenticated - call Authenticate() first")
-	}
-
-	c.logger.Info("Creating program", zap.String("program", name))
-
-	// This would require PUT/POST request to ADT program endpoint
-	// Implementation would depend on specific SAP ADT version
-	return fmt.Errorf("CreateProgram not implemented - requires SAP system configuration")
-}
-
 // Helper functions for authentication and request handling
 
 // addAuthHeaders adds authentication headers to HTTP requests
@@ -1156,7 +1531,7 @@ func (c *ADTClientImpl) getTypeSource(url, acceptType string) (string, error) {
 	c.addAuthHeaders(req)
 	req.Header.Set("Accept", acceptType)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
 	}
@@ -1173,3 +1548,154 @@ func (c *ADTClientImpl) getTypeSource(url, acceptType string) (string, error) {
 
 	return string(body), nil
 }
+
+// adtObjectURI converts a source URL into the ADT object URI (path without /source/main or /includes/...)
+func adtObjectURI(sourceURL string) string {
+	if parsed, err := url.Parse(sourceURL); err == nil {
+		sourceURL = parsed.Path
+	}
+	if idx := strings.Index(sourceURL, "/includes/"); idx > 0 && strings.Contains(sourceURL, "/oo/classes/") {
+		return sourceURL[:idx]
+	}
+	return strings.TrimSuffix(sourceURL, "/source/main")
+}
+
+// absoluteURL resolves an ADT URI (e.g. /sap/bc/adt/programs/programs/ZTEST) against the base URL
+func (c *ADTClientImpl) absoluteURL(uri string) string {
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		return uri
+	}
+	if strings.HasPrefix(uri, "/sap/bc/adt") {
+		return strings.TrimSuffix(c.baseURL, "/sap/bc/adt") + uri
+	}
+	return c.baseURL + uri
+}
+
+// doRequest sends an authenticated ADT request and returns the response with its body read
+func (c *ADTClientImpl) doRequest(method, requestURL string, body io.Reader, headers map[string]string) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.addAuthHeaders(req)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return resp, responseBody, nil
+}
+
+// adtException represents the exc:exception document returned by ADT on errors
+type adtException struct {
+	XMLName xml.Name `xml:"exception"`
+	Type    struct {
+		ID string `xml:"id,attr"`
+	} `xml:"type"`
+	Message    string `xml:"message"`
+	Properties []struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"properties>entry"`
+}
+
+// parseADTException reads an exc:exception document, it returns nil for other bodies
+func parseADTException(body []byte) *adtException {
+	var exc adtException
+	if err := xml.Unmarshal(body, &exc); err != nil {
+		return nil
+	}
+	return &exc
+}
+
+// t100Key returns the message class and number of the exception, e.g. MC/601
+func (e *adtException) t100Key() string {
+	var id, number string
+	for _, property := range e.Properties {
+		switch property.Key {
+		case "T100KEY-ID":
+			id = strings.TrimSpace(property.Value)
+		case "T100KEY-NO":
+			number = strings.TrimSpace(property.Value)
+		}
+	}
+	if id == "" {
+		return ""
+	}
+	return strings.ToUpper(id) + "/" + number
+}
+
+// adtObjectRef maps an adtcore object reference (attributes only)
+type adtObjectRef struct {
+	URI         string `xml:"uri,attr"`
+	Type        string `xml:"type,attr"`
+	Name        string `xml:"name,attr"`
+	ParentURI   string `xml:"parentUri,attr"`
+	PackageName string `xml:"packageName,attr"`
+	Description string `xml:"description,attr"`
+}
+
+// adtObjectReferences maps the adtcore:objectReferences document returned by searches
+type adtObjectReferences struct {
+	XMLName    xml.Name       `xml:"objectReferences"`
+	References []adtObjectRef `xml:"objectReference"`
+}
+
+// toADTObject converts the reference into the shared object type
+func (r adtObjectRef) toADTObject() types.ADTObject {
+	return types.ADTObject{
+		Name:        r.Name,
+		Type:        r.Type,
+		Description: r.Description,
+		Package:     r.PackageName,
+		URI:         r.URI,
+	}
+}
+
+// xmlRootName returns the local name of the root element of an XML document
+func xmlRootName(body []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+// statusError returns an error that carries the HTTP status of a failed ADT request
+func statusError(statusCode int, format string, args ...interface{}) error {
+	return &types.ADTStatusError{StatusCode: statusCode, Message: fmt.Sprintf(format, args...)}
+}
+
+// isNotFound reports whether an ADT error was caused by a missing object
+func isNotFound(err error) bool {
+	var statusErr *types.ADTStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// adtErrorMessage extracts a readable message from an ADT error response body
+func adtErrorMessage(body []byte) string {
+	var exc adtException
+	if err := xml.Unmarshal(body, &exc); err == nil && exc.Message != "" {
+		if exc.Type.ID != "" {
+			return fmt.Sprintf("%s (%s)", exc.Message, exc.Type.ID)
+		}
+		return exc.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtCompletionResponse maps the asx:abap document returned by /abapsource/codecompletion/proposal
+type adtCompletionResponse struct {
+	XMLName xml.Name `xml:"abap"`
+	Items   []struct {
+		Kind         int    `xml:"KIND"`
+		Identifier   string `xml:"IDENTIFIER"`
+		PrefixLength int    `xml:"PREFIXLENGTH"`
+		Role         int    `xml:"ROLE"`
+		Location     int    `xml:"LOCATION"`
+		Visibility   int    `xml:"VISIBILITY"`
+		IsInherited  string `xml:"IS_INHERITED"`
+		IsMeta       string `xml:"IS_META"`
+	} `xml:"values>DATA>SCC_COMPLETION"`
+}
+
+// completionRequest prepares a completion call: it resolves the source URI, loads the
+// server source if none is given and returns the URL for the cursor position
+func (c *ADTClientImpl) completionRequest(endpoint, objectURI, source string, line, column int, extra url.Values) (string, string, error) {
+	if !c.IsAuthenticated() {
+		return "", "", fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if objectURI == "" {
+		return "", "", fmt.Errorf("object URI required for code completion")
+	}
+	if line < 1 || column < 0 {
+		return "", "", fmt.Errorf("invalid source position %d:%d", line, column)
+	}
+
+	sourceURI := sourceURIOf(objectURI)
+	if source == "" {
+		current, err := c.GetSourceByURI(sourceURI)
+		if err != nil {
+			return "", "", err
+		}
+		source = current.Source
+	}
+
+	query := url.Values{
+		"uri": {fmt.Sprintf("%s#start=%d,%d", sourceURI, line, column)},
+	}
+	for key, values := range extra {
+		query[key] = values
+	}
+	return c.baseURL + endpoint + "?" + query.Encode(), source, nil
+}
+
+// GetCompletions returns the code completion proposals at line (1-based) and column (0-based).
+// If source is not empty it is used instead of the version stored on the server, e.g. for unsaved editor buffers.
+func (c *ADTClientImpl) GetCompletions(objectURI, source string, line, column int) ([]types.CompletionItem, error) {
+	requestURL, source, err := c.completionRequest(ADT_COMPLETION_PROPOSAL_ENDPOINT, objectURI, source, line, column, url.Values{
+		"signalCompleteness": {"true"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Requesting code completion",
+		zap.String("uri", objectURI),
+		zap.Int("line", line),
+		zap.Int("column", column))
+
+	resp, body, err := c.doRequest("POST", requestURL, strings.NewReader(source), map[string]string{
+		"Content-Type": "text/plain",
+		"Accept":       "application/*",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("code completion failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var completionResp adtCompletionResponse
+	if err := xml.Unmarshal(body, &completionResp); err != nil {
+		return nil, fmt.Errorf("failed to parse completion proposals: %w", err)
+	}
+
+	items := []types.CompletionItem{}
+	for _, item := range completionResp.Items {
+		// Entries like @end only signal the completeness of the list
+		if item.Identifier == "" || strings.HasPrefix(item.Identifier, "@") {
+			continue
+		}
+		items = append(items, types.CompletionItem{
+			Identifier:   item.Identifier,
+			Kind:         item.Kind,
+			PrefixLength: item.PrefixLength,
+			Role:         item.Role,
+			Location:     item.Location,
+			Visibility:   item.Visibility,
+			Inherited:    item.IsInherited == "X",
+			Meta:         item.IsMeta == "X",
+		})
+	}
+
+	c.logger.Info("Code completion retrieved successfully", zap.Int("proposal_count", len(items)))
+	return items, nil
+}
+
+// GetCompletionInsertion returns the full text inserted for a proposal, e.g. a method call
+// pattern with its parameters
+func (c *ADTClientImpl) GetCompletionInsertion(objectURI, source string, line, column int, identifier string) (string, error) {
+	if identifier == "" {
+		return "", fmt.Errorf("proposal identifier required for completion insertion")
+	}
+
+	requestURL, source, err := c.completionRequest(ADT_COMPLETION_INSERTION_ENDPOINT, objectURI, source, line, column, url.Values{
+		"patternKey": {identifier},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Info("Requesting completion insertion",
+		zap.String("uri", objectURI),
+		zap.String("identifier", identifier))
+
+	resp, body, err := c.doRequest("POST", requestURL, strings.NewReader(source), map[string]string{
+		"Content-Type": "text/plain",
+		"Accept":       "text/plain",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("completion insertion failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	return string(body), nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtCreationTemplate describes the creation document of an object type
+type adtCreationTemplate struct {
+	collection string // collection URI objects are POSTed to, %s is the container for contained objects
+	root       string // qualified root element
+	namespace  string // xmlns declaration of the root prefix
+	attributes string // additional attributes of the root element
+	body       string // additional child elements after the package reference
+	contained  bool   // object lives in a function group instead of a package
+}
+
+// adtCreationTemplates maps ADT type codes to their creation documents
+var adtCreationTemplates = map[string]adtCreationTemplate{
+	types.ObjectTypeProgram: {
+		collection: "/sap/bc/adt/programs/programs",
+		root:       "program:abapProgram",
+		namespace:  `xmlns:program="http://www.sap.com/adt/programs/programs"`,
+	},
+	types.ObjectTypeInclude: {
+		collection: "/sap/bc/adt/programs/includes",
+		root:       "include:abapInclude",
+		namespace:  `xmlns:include="http://www.sap.com/adt/programs/includes"`,
+	},
+	types.ObjectTypeClass: {
+		collection: "/sap/bc/adt/oo/classes",
+		root:       "class:abapClass",
+		namespace:  `xmlns:class="http://www.sap.com/adt/oo/classes"`,
+		attributes: `class:final="true" class:visibility="public"`,
+		body:       "  <class:superClassRef/>\n",
+	},
+	types.ObjectTypeInterface: {
+		collection: "/sap/bc/adt/oo/interfaces",
+		root:       "intf:abapInterface",
+		namespace:  `xmlns:intf="http://www.sap.com/adt/oo/interfaces"`,
+	},
+	types.ObjectTypeFunctionGroup: {
+		collection: "/sap/bc/adt/functions/groups",
+		root:       "group:abapFunctionGroup",
+		namespace:  `xmlns:group="http://www.sap.com/adt/functions/groups"`,
+	},
+	types.ObjectTypeFunction: {
+		collection: "/sap/bc/adt/functions/groups/%s/fmodules",
+		root:       "fmodule:abapFunctionModule",
+		namespace:  `xmlns:fmodule="http://www.sap.com/adt/functions/fmodules"`,
+		contained:  true,
+	},
+}
+
+// CreateObject creates an empty repository object in the given package. Function modules
+// are created in the given function group instead.
+func (c *ADTClientImpl) CreateObject(kind, name string, options types.CreateOptions) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	group := strings.ToUpper(strings.TrimSpace(options.FunctionGroup))
+	obj, err := types.ObjectReference(kind, name, group)
+	if err != nil {
+		return err
+	}
+	template, ok := adtCreationTemplates[obj.Type]
+	if !ok {
+		return fmt.Errorf("creating objects of type %s is not supported", obj.Type)
+	}
+
+	// The parent is the function group of contained objects, the package otherwise
+	parent := group
+	if !template.contained {
+		parent = strings.ToUpper(strings.TrimSpace(options.Package))
+		if parent == "" {
+			return fmt.Errorf("package required to create %s", obj.Name)
+		}
+	}
+
+	description := options.Description
+	if description == "" {
+		description = obj.Name
+	}
+	transport := options.Transport
+
+	c.logger.Info("Creating object",
+		zap.String("type", obj.Type),
+		zap.String("name", obj.Name),
+		zap.String("parent", parent),
+		zap.String("transport", transport))
+
+	attributes := ""
+	if template.attributes != "" {
+		attributes = " " + template.attributes
+	}
+
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<` + template.root + ` ` + template.namespace + ` xmlns:adtcore="http://www.sap.com/adt/core"` + attributes +
+		` adtcore:description="` + xmlText(description) + `" adtcore:name="` + xmlText(obj.Name) + `"` +
+		` adtcore:type="` + obj.Type + `" adtcore:responsible="` + xmlText(strings.ToUpper(c.config.Username)) + `">
+` + creationParentRef(template, parent) + template.body + `</` + template.root + `>
+`
+
+	collection := template.collection
+	if template.contained {
+		collection = fmt.Sprintf(collection, url.PathEscape(parent))
+	}
+
+	createURL := c.absoluteURL(collection)
+	if transport != "" {
+		createURL += "?" + url.Values{"corrNr": {transport}}.Encode()
+	}
+
+	resp, body, err := c.doRequest("POST", createURL, strings.NewReader(document), map[string]string{
+		"Content-Type": "application/*",
+		"Accept":       "application/*",
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		if resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("creation forbidden (403) - %s", adtErrorMessage(body))
+		}
+		return fmt.Errorf("failed to create %s %s: HTTP %d - %s", obj.Type, obj.Name, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	c.logger.Info("Object created successfully", zap.String("uri", obj.URI))
+	return nil
+}
+
+// creationParentRef returns the package reference, or the function group reference of contained objects
+func creationParentRef(template adtCreationTemplate, parent string) string {
+	if template.contained {
+		return `  <adtcore:containerRef adtcore:name="` + xmlText(parent) + `" adtcore:type="` + types.ObjectTypeFunctionGroup +
+			`" adtcore:uri="/sap/bc/adt/functions/groups/` + xmlText(strings.ToLower(parent)) + `"/>
+`
+	}
+	return `  <adtcore:packageRef adtcore:name="` + xmlText(parent) + `"/>
+`
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtFormatterSettings maps the PrettyPrinterSettings document
+type adtFormatterSettings struct {
+	XMLName     xml.Name `xml:"PrettyPrinterSettings"`
+	Indentation bool     `xml:"indentation,attr"`
+	Style       string   `xml:"style,attr"`
+}
+
+// Format runs the ABAP pretty printer on a source. The server applies the formatter
+// settings of the current user (see GetFormatterSettings).
+func (c *ADTClientImpl) Format(source string) (string, error) {
+	if !c.IsAuthenticated() {
+		return "", fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Formatting source", zap.Int("source_length", len(source)))
+
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_PRETTY_PRINTER_ENDPOINT, strings.NewReader(source), map[string]string{
+		"Content-Type": "text/plain; charset=utf-8",
+		"Accept":       "text/plain",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("formatting failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	return string(body), nil
+}
+
+// GetFormatterSettings returns the pretty printer settings (keyword case and indentation) used by Format
+func (c *ADTClientImpl) GetFormatterSettings() (*types.ADTFormatterSettings, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Retrieving formatter settings")
+
+	resp, body, err := c.doRequest("GET", c.baseURL+ADT_PRETTY_PRINTER_SETTINGS_ENDPOINT, nil, map[string]string{
+		"Accept": "application/vnd.sap.adt.ppsettings.v2+xml, application/vnd.sap.adt.ppsettings+xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get formatter settings: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var settings adtFormatterSettings
+	if err := xml.Unmarshal(body, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse formatter settings: %w", err)
+	}
+
+	return &types.ADTFormatterSettings{
+		Indentation: settings.Indentation,
+		Style:       settings.Style,
+	}, nil
+}
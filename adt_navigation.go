@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtNavigationTarget maps the adtcore:objectReference returned by /navigation/target
+type adtNavigationTarget struct {
+	XMLName xml.Name `xml:"objectReference"`
+	adtObjectRef
+}
+
+// sourceURIOf returns the source URI of an object URI (source URIs are returned unchanged)
+func sourceURIOf(objectURI string) string {
+	if strings.Contains(objectURI, "/source/") || strings.Contains(objectURI, "/includes/") {
+		return objectURI
+	}
+	return strings.TrimSuffix(objectURI, "/") + "/source/main"
+}
+
+// isIdentifierChar reports whether r can be part of an ABAP identifier (including namespaces and interface components)
+func isIdentifierChar(r rune) bool {
+	return r == '_' || r == '/' || r == '~' ||
+		(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// identifierRange returns the 0-based start and end column of the identifier at line/column in source
+func identifierRange(source string, line, column int) (int, int) {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return column, column
+	}
+
+	text := []rune(strings.TrimRight(lines[line-1], "\r"))
+	if column < 0 || column >= len(text) || !isIdentifierChar(text[column]) {
+		return column, column
+	}
+
+	start, end := column, column
+	for start > 0 && isIdentifierChar(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isIdentifierChar(text[end]) {
+		end++
+	}
+	return start, end
+}
+
+// FindDefinition resolves the symbol at line (1-based) and column (0-based) of an object's source to its definition.
+// If source is not empty it is used instead of the version stored on the server, e.g. for unsaved editor buffers.
+func (c *ADTClientImpl) FindDefinition(objectURI, source string, line, column int) (*types.ADTDefinition, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if objectURI == "" {
+		return nil, fmt.Errorf("object URI required for navigation")
+	}
+	if line < 1 || column < 0 {
+		return nil, fmt.Errorf("invalid source position %d:%d", line, column)
+	}
+
+	sourceURI := sourceURIOf(objectURI)
+	if source == "" {
+		current, err := c.GetSourceByURI(sourceURI)
+		if err != nil {
+			return nil, err
+		}
+		source = current.Source
+	}
+	start, end := identifierRange(source, line, column)
+
+	c.logger.Info("Finding definition",
+		zap.String("uri", sourceURI),
+		zap.Int("line", line),
+		zap.Int("start", start),
+		zap.Int("end", end))
+
+	query := url.Values{
+		"uri":    {fmt.Sprintf("%s#start=%d,%d;end=%d,%d", sourceURI, line, start, line, end)},
+		"filter": {"definition"},
+	}
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_NAVIGATION_TARGET_ENDPOINT+"?"+query.Encode(), strings.NewReader(source), map[string]string{
+		"Content-Type": "text/plain",
+		"Accept":       "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "no definition found at %d:%d (404)", line, column)
+		}
+		return nil, fmt.Errorf("navigation failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var target adtNavigationTarget
+	if err := xml.Unmarshal(body, &target); err != nil {
+		return nil, fmt.Errorf("failed to parse navigation target: %w", err)
+	}
+	if target.URI == "" {
+		return nil, fmt.Errorf("no definition found at %d:%d", line, column)
+	}
+
+	definition := &types.ADTDefinition{Object: target.toADTObject()}
+	definition.URI, definition.Line, definition.Column = parseSourcePosition(target.URI)
+	definition.Object.URI = adtObjectURI(definition.URI)
+
+	c.logger.Info("Definition found",
+		zap.String("uri", definition.URI),
+		zap.Int("line", definition.Line))
+
+	return definition, nil
+}
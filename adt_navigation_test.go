@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestIdentifierRange(t *testing.T) {
+	source := "REPORT ztest.\n  WRITE 'Grüße' TO lv_text.\r\n  /abc/cl_ä=>run( ).\n"
+	tests := []struct {
+		name         string
+		line, column int
+		wantStart    int
+		wantEnd      int
+	}{
+		{"keyword", 1, 3, 0, 6},
+		{"identifier after umlauts", 2, 21, 19, 26},
+		{"start of identifier after umlauts", 2, 19, 19, 26},
+		{"umlaut is no identifier", 2, 11, 11, 11},
+		{"namespace up to umlaut", 3, 4, 2, 10},
+		{"method after umlaut", 3, 13, 13, 16},
+		{"past end of line", 2, 40, 40, 40},
+		{"line out of range", 9, 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := identifierRange(source, tt.line, tt.column)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("identifierRange(%d, %d) = %d, %d, want %d, %d", tt.line, tt.column, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// contextCookie binds requests to the stateful context of one process (the locks it holds),
+// it is never persisted so concurrent processes that continue a session only share the logon
+const contextCookie = "sap-contextid"
+
+// adtSession is the login state of a client: the session cookies (SAP_SESSIONID_*,
+// MYSAPSSO2) and the CSRF token. It is persisted so later invocations skip the logon.
+type adtSession struct {
+	Host      string          `json:"host"`
+	Client    string          `json:"client"`
+	User      string          `json:"user"`
+	CSRFToken string          `json:"csrf_token"`
+	Cookies   []sessionCookie `json:"cookies"`
+	LastUsed  time.Time       `json:"last_used"`
+}
+
+// sessionCookie is a cookie of the session
+type sessionCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// sessionJar is a cookie jar that can be emptied while requests are using it
+type sessionJar struct {
+	mu  sync.Mutex
+	jar *cookiejar.Jar
+}
+
+func newSessionJar() *sessionJar {
+	jar, _ := cookiejar.New(nil)
+	return &sessionJar{jar: jar}
+}
+
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+}
+
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+// reset drops all cookies
+func (j *sessionJar) reset() {
+	jar, _ := cookiejar.New(nil)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar = jar
+}
+
+// systemURL returns the root URL of the system, which all session cookies apply to
+func (c *ADTClientImpl) systemURL() *url.URL {
+	u, err := url.Parse(strings.TrimSuffix(c.baseURL, "/sap/bc/adt") + "/")
+	if err != nil {
+		return &url.URL{}
+	}
+	return u
+}
+
+// session returns the current login state
+func (c *ADTClientImpl) session() *adtSession {
+	s := &adtSession{
+		Host:      c.config.Host,
+		Client:    c.config.Client,
+		User:      strings.ToUpper(c.config.Username),
+		CSRFToken: c.csrfToken,
+		LastUsed:  time.Now(),
+	}
+	for _, cookie := range c.jar.Cookies(c.systemURL()) {
+		if strings.EqualFold(cookie.Name, contextCookie) {
+			continue
+		}
+		s.Cookies = append(s.Cookies, sessionCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return s
+}
+
+// restoreSession continues a persisted session without logging on. Other processes may use
+// the same session, so requests are stateless; lock and unlock open a stateful context of
+// this process.
+func (c *ADTClientImpl) restoreSession(s *adtSession) {
+	var cookies []*http.Cookie
+	for _, cookie := range s.Cookies {
+		if strings.EqualFold(cookie.Name, contextCookie) {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/"})
+	}
+	c.jar.SetCookies(c.systemURL(), cookies)
+	c.csrfToken = s.CSRFToken
+	c.authenticated = true
+	c.SetSessionType(types.SessionStateless)
+
+	c.logger.Info("Restored ADT session",
+		zap.Int("cookies", len(s.Cookies)),
+		zap.Duration("age", time.Since(s.LastUsed)))
+}
+
+// sessionRejected reports whether the system refused a request because the session or its
+// CSRF token expired
+func sessionRejected(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+		return strings.EqualFold(resp.Header.Get("X-CSRF-Token"), "Required")
+	default:
+		return false
+	}
+}
+
+// do sends an ADT request. When the session has expired it logs on again and repeats
+// the request once.
+func (c *ADTClientImpl) do(req *http.Request) (*http.Response, error) {
+	token := c.csrfToken
+	resp, err := c.httpClient.Do(req)
+	if err != nil || !c.authenticated || !sessionRejected(resp) {
+		return resp, err
+	}
+	// A consumed body cannot be sent again
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	c.logger.Info("ADT session expired, logging on again",
+		zap.Int("status_code", resp.StatusCode),
+		zap.String("url", req.URL.String()))
+
+	c.authMu.Lock()
+	// A concurrent request may have logged on already
+	if c.csrfToken == token {
+		// Start from an empty cookie jar, stale session cookies can fail the new logon
+		c.jar.reset()
+		c.authenticated, c.csrfToken = false, ""
+		if err := c.Authenticate(); err != nil {
+			c.authMu.Unlock()
+			return nil, fmt.Errorf("session expired and logon failed: %w", err)
+		}
+	}
+	c.authMu.Unlock()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to repeat request: %w", err)
+		}
+		retry.Body = body
+	}
+	retry.Header.Del("Cookie")
+	c.addAuthHeaders(retry)
+	return c.httpClient.Do(retry)
+}
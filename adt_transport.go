@@ -0,0 +1,385 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// ADT_TRANSPORT_CHECK_CONTENT_TYPE is the asx:abap data name of transport check requests and results
+const ADT_TRANSPORT_CHECK_CONTENT_TYPE = "application/vnd.sap.as+xml; charset=UTF-8; dataname=com.sap.adt.transport.service.checkData"
+
+// adtTransportObject maps a tm:abap_object element
+type adtTransportObject struct {
+	PgmID       string `xml:"pgmid,attr"`
+	Type        string `xml:"type,attr"`
+	Name        string `xml:"name,attr"`
+	WBType      string `xml:"wbtype,attr"`
+	Description string `xml:"obj_desc,attr"`
+	URI         string `xml:"uri,attr"`
+}
+
+// adtTransportRequest maps a tm:request or tm:task element
+type adtTransportRequest struct {
+	Number      string                `xml:"number,attr"`
+	Owner       string                `xml:"owner,attr"`
+	Description string                `xml:"desc,attr"`
+	Status      string                `xml:"status,attr"`
+	Type        string                `xml:"type,attr"`
+	Objects     []adtTransportObject  `xml:"abap_object"`
+	Tasks       []adtTransportRequest `xml:"task"`
+}
+
+// adtTransportTarget maps a tm:target element with its modifiable requests
+type adtTransportTarget struct {
+	Name     string                `xml:"name,attr"`
+	Requests []adtTransportRequest `xml:"modifiable>request"`
+}
+
+// adtTransportTree maps the tm:root document returned for a user's requests
+type adtTransportTree struct {
+	XMLName     xml.Name             `xml:"root"`
+	Workbench   []adtTransportTarget `xml:"workbench>target"`
+	Customizing []adtTransportTarget `xml:"customizing>target"`
+}
+
+// adtTransportCheckResponse maps the asx:abap document returned by /cts/transportchecks
+type adtTransportCheckResponse struct {
+	XMLName xml.Name `xml:"abap"`
+	Data    struct {
+		Recording    string `xml:"RECORDING"`
+		Package      string `xml:"DEVCLASS"`
+		DeliveryUnit string `xml:"DLVUNIT"`
+		LockedIn     string `xml:"LOCKS>CTS_OBJECT_LOCK>LOCK_HOLDER>REQ_HEADER>TRKORR"`
+		Transports   []struct {
+			Number      string `xml:"TRKORR"`
+			Owner       string `xml:"AS4USER"`
+			Description string `xml:"AS4TEXT"`
+			Function    string `xml:"TRFUNCTION"`
+			Status      string `xml:"TRSTATUS"`
+		} `xml:"TRANSPORTS>headers"`
+		Messages []struct {
+			Severity string `xml:"SEVERITY"`
+			Text     string `xml:"TEXT"`
+		} `xml:"MESSAGES>CTS_MESSAGE"`
+	} `xml:"values>DATA"`
+}
+
+// adtReleaseResponse maps the tm:root document returned by a release job
+type adtReleaseResponse struct {
+	XMLName xml.Name         `xml:"root"`
+	Reports []adtCheckReport `xml:"releasereports>checkReport"`
+}
+
+// toADTTransport converts a request or task including its objects and tasks
+func (r adtTransportRequest) toADTTransport(target string) types.ADTTransport {
+	transport := types.ADTTransport{
+		RequestID:   r.Number,
+		Description: r.Description,
+		Status:      r.Status,
+		Owner:       r.Owner,
+		Objects:     []types.ADTObject{},
+		Type:        r.Type,
+		Target:      target,
+	}
+
+	for _, obj := range r.Objects {
+		objectType := obj.WBType
+		if objectType == "" {
+			objectType = obj.PgmID + " " + obj.Type
+		}
+		transport.Objects = append(transport.Objects, types.ADTObject{
+			Name:        obj.Name,
+			Type:        objectType,
+			Description: obj.Description,
+			URI:         obj.URI,
+		})
+	}
+
+	for _, task := range r.Tasks {
+		transport.Tasks = append(transport.Tasks, task.toADTTransport(target))
+	}
+
+	return transport
+}
+
+// GetTransports lists the modifiable transport requests of the current user with their tasks and objects
+func (c *ADTClientImpl) GetTransports() ([]types.ADTTransport, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Retrieving transport requests")
+
+	query := url.Values{
+		"user":    {strings.ToUpper(c.config.Username)},
+		"targets": {"true"},
+	}
+	resp, body, err := c.doRequest("GET", c.baseURL+ADT_TRANSPORT_REQUESTS_ENDPOINT+"?"+query.Encode(), nil, map[string]string{
+		"Accept": "application/vnd.sap.adt.transportorganizertree.v1+xml, application/vnd.sap.adt.transportorganizer.v1+xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list transport requests: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var tree adtTransportTree
+	if err := xml.Unmarshal(body, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse transport requests: %w", err)
+	}
+
+	transports := []types.ADTTransport{}
+	for _, target := range append(tree.Workbench, tree.Customizing...) {
+		for _, request := range target.Requests {
+			transports = append(transports, request.toADTTransport(target.Name))
+		}
+	}
+
+	c.logger.Info("Transport requests retrieved successfully", zap.Int("request_count", len(transports)))
+	return transports, nil
+}
+
+// CreateTransport creates a workbench request for a package and returns its number.
+// If objectURI is given the request is created for that object.
+func (c *ADTClientImpl) CreateTransport(description, packageName, objectURI string) (string, error) {
+	if !c.IsAuthenticated() {
+		return "", fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if description == "" {
+		return "", fmt.Errorf("description required for a transport request")
+	}
+	if packageName == "" {
+		return "", fmt.Errorf("package required for a transport request")
+	}
+
+	c.logger.Info("Creating transport request",
+		zap.String("package", packageName),
+		zap.String("uri", objectURI))
+
+	payload := `<?xml version="1.0" encoding="ASCII"?>
+<asx:abap xmlns:asx="http://www.sap.com/abapxml" version="1.0">
+  <asx:values>
+    <DATA>
+      <OPERATION>I</OPERATION>
+      <DEVCLASS>` + xmlText(strings.ToUpper(packageName)) + `</DEVCLASS>
+      <REQUEST_TEXT>` + xmlText(description) + `</REQUEST_TEXT>
+      <REF>` + xmlText(objectURI) + `</REF>
+    </DATA>
+  </asx:values>
+</asx:abap>`
+
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_TRANSPORTS_ENDPOINT, strings.NewReader(payload), map[string]string{
+		"Content-Type": "application/vnd.sap.as+xml; charset=UTF-8; dataname=com.sap.adt.CreateCorrectionRequest",
+		"Accept":       "text/plain",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to create transport request: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	// The response is the request URI, e.g. /com.sap.cts/object_record/DEVK900123
+	location := strings.TrimSpace(string(body))
+	requestID := location[strings.LastIndex(location, "/")+1:]
+	if requestID == "" {
+		return "", fmt.Errorf("transport creation response did not contain a request number")
+	}
+
+	c.logger.Info("Transport request created successfully", zap.String("request", requestID))
+	return requestID, nil
+}
+
+// CheckTransport determines whether changes to an object need a transport request and which ones can be used
+func (c *ADTClientImpl) CheckTransport(objectURI, packageName string) (*types.ADTTransportCheck, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if objectURI == "" {
+		return nil, fmt.Errorf("object URI required for transport check")
+	}
+
+	c.logger.Info("Checking transport requirements", zap.String("uri", objectURI))
+
+	payload := `<?xml version="1.0" encoding="UTF-8"?>
+<asx:abap xmlns:asx="http://www.sap.com/abapxml" version="1.0">
+  <asx:values>
+    <DATA>
+      <PGMID/>
+      <OBJECT/>
+      <OBJECTNAME/>
+      <DEVCLASS>` + xmlText(strings.ToUpper(packageName)) + `</DEVCLASS>
+      <OPERATION/>
+      <URI>` + xmlText(objectURI) + `</URI>
+    </DATA>
+  </asx:values>
+</asx:abap>`
+
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_TRANSPORT_CHECKS_ENDPOINT, strings.NewReader(payload), map[string]string{
+		"Content-Type": ADT_TRANSPORT_CHECK_CONTENT_TYPE,
+		"Accept":       ADT_TRANSPORT_CHECK_CONTENT_TYPE,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transport check failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var checkResp adtTransportCheckResponse
+	if err := xml.Unmarshal(body, &checkResp); err != nil {
+		return nil, fmt.Errorf("failed to parse transport check response: %w", err)
+	}
+
+	data := checkResp.Data
+	result := &types.ADTTransportCheck{
+		Recording:    data.Recording == "X",
+		Package:      data.Package,
+		DeliveryUnit: data.DeliveryUnit,
+		LockedIn:     data.LockedIn,
+	}
+	for _, header := range data.Transports {
+		result.Transports = append(result.Transports, types.ADTTransport{
+			RequestID:   header.Number,
+			Description: header.Description,
+			Status:      header.Status,
+			Owner:       header.Owner,
+			Objects:     []types.ADTObject{},
+			Type:        header.Function,
+		})
+	}
+	for _, msg := range data.Messages {
+		result.Messages = append(result.Messages, msg.Text)
+	}
+
+	c.logger.Info("Transport check completed",
+		zap.String("uri", objectURI),
+		zap.Bool("recording", result.Recording),
+		zap.String("locked_in", result.LockedIn))
+
+	return result, nil
+}
+
+// AssignTransport records an object in a transport request or task. ADT records objects in
+// the request a change is saved with, so the unchanged source is written back with corrNr.
+func (c *ADTClientImpl) AssignTransport(objectURI, requestID string) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	requestID = strings.ToUpper(strings.TrimSpace(requestID))
+	if requestID == "" {
+		return fmt.Errorf("transport request number required")
+	}
+	if objectURI == "" {
+		return fmt.Errorf("object URI required for transport assignment")
+	}
+
+	c.logger.Info("Assigning object to transport request",
+		zap.String("uri", objectURI),
+		zap.String("request", requestID))
+
+	lock, err := c.LockObject(objectURI)
+	if err != nil {
+		return err
+	}
+
+	// Always release the lock, even if the assignment fails
+	defer func() {
+		if unlockErr := c.UnlockObject(objectURI, lock.LockHandle); unlockErr != nil {
+			c.logger.Warn("Failed to release lock", zap.String("uri", objectURI), zap.Error(unlockErr))
+		}
+	}()
+
+	if lock.IsLocal {
+		return fmt.Errorf("%s is a local object and cannot be recorded in a transport request", objectURI)
+	}
+	if lock.CorrNr != "" {
+		if strings.EqualFold(lock.CorrNr, requestID) {
+			c.logger.Info("Object already recorded in transport request",
+				zap.String("uri", objectURI),
+				zap.String("request", requestID))
+			return nil
+		}
+		return fmt.Errorf("%s is already locked in request %s (%s)", objectURI, lock.CorrNr, lock.CorrText)
+	}
+
+	source, err := c.GetSourceByURI(objectURI + "/source/main")
+	if err != nil {
+		return err
+	}
+	if _, err := c.writeSource(source, lock.LockHandle, requestID); err != nil {
+		return err
+	}
+
+	c.logger.Info("Object assigned to transport request successfully",
+		zap.String("uri", objectURI),
+		zap.String("request", requestID))
+
+	return nil
+}
+
+// ReleaseTransport releases a transport request or task
+func (c *ADTClientImpl) ReleaseTransport(requestID string) (*types.ADTReleaseResult, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	requestID = strings.ToUpper(strings.TrimSpace(requestID))
+	if requestID == "" {
+		return nil, fmt.Errorf("transport request number required")
+	}
+
+	c.logger.Info("Releasing transport request", zap.String("request", requestID))
+
+	releaseURL := c.baseURL + ADT_TRANSPORT_REQUESTS_ENDPOINT + "/" + url.PathEscape(requestID) + "/newreleasejobs"
+	resp, body, err := c.doRequest("POST", releaseURL, nil, map[string]string{
+		"Accept": "application/*",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "transport request %s not found (404)", requestID)
+		}
+		return nil, fmt.Errorf("failed to release %s: HTTP %d - %s", requestID, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var releaseResp adtReleaseResponse
+	if err := xml.Unmarshal(body, &releaseResp); err != nil {
+		return nil, fmt.Errorf("failed to parse release response: %w", err)
+	}
+
+	result := &types.ADTReleaseResult{RequestID: requestID}
+	for _, report := range releaseResp.Reports {
+		result.Status = report.Status
+		result.Text = report.StatusText
+		for _, msg := range report.Messages {
+			result.Messages = append(result.Messages, types.ADTSyntaxMessage{
+				URI:      msg.URI,
+				Type:     msg.Type,
+				Severity: messageSeverity(msg.Type),
+				Text:     msg.ShortText,
+			})
+		}
+	}
+	result.Released = result.Status == "released"
+
+	c.logger.Info("Transport release completed",
+		zap.String("request", requestID),
+		zap.String("status", result.Status))
+
+	return result, nil
+}
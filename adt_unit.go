@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtUnitDetail maps a (possibly nested) alert detail
+type adtUnitDetail struct {
+	Text    string          `xml:"text,attr"`
+	Details []adtUnitDetail `xml:"details>detail"`
+}
+
+// adtUnitAlert maps an aunit alert
+type adtUnitAlert struct {
+	Kind     string          `xml:"kind,attr"`
+	Severity string          `xml:"severity,attr"`
+	Title    string          `xml:"title"`
+	Details  []adtUnitDetail `xml:"details>detail"`
+	Stack    []adtObjectRef  `xml:"stack>stackEntry"`
+}
+
+// adtUnitRunResult maps the aunit:runResult document
+type adtUnitRunResult struct {
+	XMLName  xml.Name `xml:"runResult"`
+	Programs []struct {
+		adtObjectRef
+		Classes []struct {
+			adtObjectRef
+			DurationCategory string `xml:"durationCategory,attr"`
+			RiskLevel        string `xml:"riskLevel,attr"`
+			Methods          []struct {
+				adtObjectRef
+				ExecutionTime string         `xml:"executionTime,attr"`
+				Alerts        []adtUnitAlert `xml:"alerts>alert"`
+			} `xml:"testMethods>testMethod"`
+			Alerts []adtUnitAlert `xml:"alerts>alert"`
+		} `xml:"testClasses>testClass"`
+		Alerts []adtUnitAlert `xml:"alerts>alert"`
+	} `xml:"program"`
+}
+
+// RunUnitTests runs ABAP Unit tests for the given objects
+func (c *ADTClientImpl) RunUnitTests(objects []types.ADTObject) (*types.ADTUnitRunResult, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects to test")
+	}
+
+	c.logger.Info("Running ABAP Unit tests", zap.Int("objects", len(objects)))
+
+	var payload bytes.Buffer
+	payload.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	payload.WriteString(`<aunit:runConfiguration xmlns:aunit="http://www.sap.com/adt/aunit">`)
+	payload.WriteString(`<external><coverage active="false"/></external>`)
+	payload.WriteString(`<options>`)
+	payload.WriteString(`<uriType value="semantic"/>`)
+	payload.WriteString(`<testDeterminationStrategy sameProgram="true" assignedTests="false"/>`)
+	payload.WriteString(`<testRiskLevels harmless="true" dangerous="true" critical="true"/>`)
+	payload.WriteString(`<testDurations short="true" medium="true" long="true"/>`)
+	payload.WriteString(`<withNavigationUri enabled="false"/>`)
+	payload.WriteString(`</options>`)
+	payload.WriteString(`<adtcore:objectSets xmlns:adtcore="http://www.sap.com/adt/core"><objectSet kind="inclusive"><adtcore:objectReferences>`)
+	for _, obj := range objects {
+		payload.WriteString(`<adtcore:objectReference adtcore:uri="`)
+		xml.EscapeText(&payload, []byte(obj.URI))
+		payload.WriteString(`"/>`)
+	}
+	payload.WriteString(`</adtcore:objectReferences></objectSet></adtcore:objectSets></aunit:runConfiguration>`)
+
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_UNIT_TESTRUNS_ENDPOINT, &payload, map[string]string{
+		"Content-Type": "application/xml",
+		"Accept":       "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ABAP Unit run failed: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	result, err := parseUnitRunResult(body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("ABAP Unit run completed",
+		zap.Int("tests", result.Tests),
+		zap.Int("failures", result.Failures),
+		zap.Int("errors", result.Errors))
+
+	return result, nil
+}
+
+// parseUnitRunResult converts an aunit:runResult document into the shared result type
+func parseUnitRunResult(body []byte) (*types.ADTUnitRunResult, error) {
+	result := &types.ADTUnitRunResult{Programs: []types.ADTUnitProgram{}}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return result, nil
+	}
+
+	var run adtUnitRunResult
+	if err := xml.Unmarshal(body, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse ABAP Unit result: %w", err)
+	}
+
+	for _, p := range run.Programs {
+		program := types.ADTUnitProgram{
+			Name:    p.Name,
+			Type:    p.Type,
+			URI:     p.URI,
+			Classes: []types.ADTUnitClass{},
+			Alerts:  convertUnitAlerts(p.Alerts),
+		}
+		for _, cl := range p.Classes {
+			class := types.ADTUnitClass{
+				Name:             cl.Name,
+				URI:              cl.URI,
+				DurationCategory: cl.DurationCategory,
+				RiskLevel:        cl.RiskLevel,
+				Methods:          []types.ADTUnitMethod{},
+				Alerts:           convertUnitAlerts(cl.Alerts),
+			}
+			countUnitSetup(result, class.Alerts)
+			for _, m := range cl.Methods {
+				method := types.ADTUnitMethod{
+					Name:   m.Name,
+					URI:    m.URI,
+					Alerts: convertUnitAlerts(m.Alerts),
+				}
+				method.ExecutionTime, _ = strconv.ParseFloat(m.ExecutionTime, 64)
+				result.Tests++
+				switch outcome, _ := types.UnitOutcome(method.Alerts); outcome {
+				case types.UnitFailed:
+					result.Failures++
+				case types.UnitError:
+					result.Errors++
+				}
+				class.Methods = append(class.Methods, method)
+			}
+			program.Classes = append(program.Classes, class)
+		}
+		countUnitSetup(result, program.Alerts)
+		result.Programs = append(result.Programs, program)
+	}
+
+	return result, nil
+}
+
+// convertUnitAlerts flattens alert details and stack entries
+func convertUnitAlerts(alerts []adtUnitAlert) []types.ADTUnitAlert {
+	var converted []types.ADTUnitAlert
+	for _, a := range alerts {
+		alert := types.ADTUnitAlert{
+			Kind:     a.Kind,
+			Severity: a.Severity,
+			Title:    a.Title,
+			Details:  flattenUnitDetails(a.Details, nil),
+		}
+		for _, entry := range a.Stack {
+			alert.Stack = append(alert.Stack, types.ADTUnitStackEntry{
+				URI:         entry.URI,
+				Type:        entry.Type,
+				Name:        entry.Name,
+				Description: entry.Description,
+			})
+		}
+		converted = append(converted, alert)
+	}
+	return converted
+}
+
+// flattenUnitDetails collects nested detail texts depth-first
+func flattenUnitDetails(details []adtUnitDetail, texts []string) []string {
+	for _, d := range details {
+		if d.Text != "" {
+			texts = append(texts, d.Text)
+		}
+		texts = flattenUnitDetails(d.Details, texts)
+	}
+	return texts
+}
+
+// countUnitSetup counts failing class or program level alerts (e.g. a failing setup) as
+// an erroneous test, the way the JUnit report lists them
+func countUnitSetup(result *types.ADTUnitRunResult, alerts []types.ADTUnitAlert) {
+	if outcome, _ := types.UnitOutcome(alerts); outcome != types.UnitPassed {
+		result.Tests++
+		result.Errors++
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtUsageReferenceResult maps the usageReferences:usageReferenceResult document
+type adtUsageReferenceResult struct {
+	XMLName xml.Name `xml:"usageReferenceResult"`
+	Objects []struct {
+		URI              string `xml:"uri,attr"`
+		ParentURI        string `xml:"parentUri,attr"`
+		IsResult         bool   `xml:"isResult,attr"`
+		UsageInformation string `xml:"usageInformation,attr"`
+		ObjectIdentifier string `xml:"objectIdentifier,attr"`
+		ADTObject        struct {
+			adtObjectRef
+			PackageRef adtObjectRef `xml:"packageRef"`
+		} `xml:"adtObject"`
+	} `xml:"referencedObjects>referencedObject"`
+}
+
+// adtUsageSnippetResult maps the usageSnippets:usageSnippetResult document
+type adtUsageSnippetResult struct {
+	XMLName xml.Name `xml:"usageSnippetResult"`
+	Objects []struct {
+		Object struct {
+			ObjectIdentifier string `xml:"objectIdentifier,attr"`
+		} `xml:"object"`
+		Snippets []struct {
+			URI         string `xml:"uri,attr"`
+			Content     string `xml:"content"`
+			Description string `xml:"description"`
+		} `xml:"codeSnippets>codeSnippet"`
+	} `xml:"codeSnippetObjects>codeSnippetObject"`
+}
+
+// WhereUsed returns the objects that use the given object with the source locations of each usage
+func (c *ADTClientImpl) WhereUsed(objectURI string) ([]types.ADTUsageReference, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Retrieving usage references", zap.String("uri", objectURI))
+
+	requestBody := `<?xml version="1.0" encoding="UTF-8"?>
+<usagereferences:usageReferenceRequest xmlns:usagereferences="http://www.sap.com/adt/ris/usageReferences">
+  <usagereferences:affectedObjects/>
+</usagereferences:usageReferenceRequest>`
+
+	query := url.Values{"uri": {objectURI}}
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_USAGE_REFERENCES_ENDPOINT+"?"+query.Encode(), strings.NewReader(requestBody), map[string]string{
+		"Content-Type": "application/vnd.sap.adt.repository.usagereferences.request.v1+xml",
+		"Accept":       "application/vnd.sap.adt.repository.usagereferences.result.v1+xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "object %s not found (404)", objectURI)
+		}
+		return nil, fmt.Errorf("failed to get usage references of %s: HTTP %d - %s", objectURI, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var result adtUsageReferenceResult
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse usage references: %w", err)
+	}
+
+	var usages []types.ADTUsageReference
+	for _, ref := range result.Objects {
+		// Package and container nodes only structure the result tree
+		if !ref.IsResult {
+			continue
+		}
+
+		obj := ref.ADTObject.toADTObject()
+		obj.URI = ref.URI
+		if obj.Package == "" {
+			obj.Package = ref.ADTObject.PackageRef.Name
+		}
+
+		usages = append(usages, types.ADTUsageReference{
+			Object:           obj,
+			ParentURI:        ref.ParentURI,
+			UsageInformation: ref.UsageInformation,
+			ObjectIdentifier: ref.ObjectIdentifier,
+		})
+	}
+
+	// Snippets are optional detail, the references are still useful without them
+	if err := c.addUsageSnippets(objectURI, usages); err != nil {
+		c.logger.Warn("Failed to retrieve usage snippets", zap.String("uri", objectURI), zap.Error(err))
+	}
+
+	c.logger.Info("Usage references retrieved successfully",
+		zap.String("uri", objectURI),
+		zap.Int("usage_count", len(usages)))
+
+	return usages, nil
+}
+
+// foreignUsages drops the usages by parts of the object itself, e.g. the test classes of a
+// class or the includes and function modules of a function group
+func foreignUsages(objectURI string, usages []types.ADTUsageReference) []types.ADTUsageReference {
+	own := strings.ToLower(strings.TrimSuffix(objectURI, "/"))
+	var foreign []types.ADTUsageReference
+	for _, usage := range usages {
+		uri := strings.ToLower(usage.Object.URI)
+		if uri == own || strings.HasPrefix(uri, own+"/") || strings.HasPrefix(uri, own+"#") {
+			continue
+		}
+		foreign = append(foreign, usage)
+	}
+	return foreign
+}
+
+// addUsageSnippets fills the source locations of usage references
+func (c *ADTClientImpl) addUsageSnippets(objectURI string, usages []types.ADTUsageReference) error {
+	var identifiers strings.Builder
+	for _, usage := range usages {
+		if usage.ObjectIdentifier == "" {
+			continue
+		}
+		identifiers.WriteString(`    <usagesnippets:objectIdentifier optional="false" uri="` + xmlText(usage.ObjectIdentifier) + `" parentUri="` + xmlText(usage.ParentURI) + `"/>
+`)
+	}
+	if identifiers.Len() == 0 {
+		return nil
+	}
+
+	requestBody := `<?xml version="1.0" encoding="UTF-8"?>
+<usagesnippets:usageSnippetRequest xmlns:usagesnippets="http://www.sap.com/adt/ris/usageSnippets">
+  <usagesnippets:objectIdentifiers>
+` + identifiers.String() + `  </usagesnippets:objectIdentifiers>
+  <usagesnippets:affectedObjects/>
+</usagesnippets:usageSnippetRequest>`
+
+	query := url.Values{"uri": {objectURI}}
+	resp, body, err := c.doRequest("POST", c.baseURL+ADT_USAGE_SNIPPETS_ENDPOINT+"?"+query.Encode(), strings.NewReader(requestBody), map[string]string{
+		"Content-Type": "application/vnd.sap.adt.repository.usagesnippets.request.v1+xml",
+		"Accept":       "application/vnd.sap.adt.repository.usagesnippets.result.v1+xml",
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var result adtUsageSnippetResult
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse usage snippets: %w", err)
+	}
+
+	snippetsByIdentifier := make(map[string][]types.ADTUsageSnippet)
+	for _, obj := range result.Objects {
+		for _, snippet := range obj.Snippets {
+			usageSnippet := types.ADTUsageSnippet{
+				Content:     strings.TrimSpace(snippet.Content),
+				Description: snippet.Description,
+			}
+			usageSnippet.URI, usageSnippet.Line, usageSnippet.Column = parseSourcePosition(snippet.URI)
+			snippetsByIdentifier[obj.Object.ObjectIdentifier] = append(snippetsByIdentifier[obj.Object.ObjectIdentifier], usageSnippet)
+		}
+	}
+
+	for i := range usages {
+		usages[i].Snippets = snippetsByIdentifier[usages[i].ObjectIdentifier]
+	}
+	return nil
+}
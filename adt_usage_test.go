@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bluefunda/abaper/types"
+)
+
+func TestForeignUsages(t *testing.T) {
+	usage := func(uri string) types.ADTUsageReference {
+		return types.ADTUsageReference{Object: types.ADTObject{URI: uri}}
+	}
+	tests := []struct {
+		name      string
+		objectURI string
+		usages    []string
+		want      []string
+	}{
+		{
+			name:      "class test include",
+			objectURI: "/sap/bc/adt/oo/classes/zcl_sales",
+			usages:    []string{"/sap/bc/adt/oo/classes/zcl_sales/includes/testclasses", "/sap/bc/adt/programs/programs/zreport"},
+			want:      []string{"/sap/bc/adt/programs/programs/zreport"},
+		},
+		{
+			name:      "function group parts",
+			objectURI: "/sap/bc/adt/functions/groups/ztax",
+			usages:    []string{"/sap/bc/adt/functions/groups/ztax/includes/lztaxtop", "/sap/bc/adt/functions/groups/ztax/fmodules/z_calculate_tax"},
+			want:      nil,
+		},
+		{
+			name:      "source position of the object itself",
+			objectURI: "/sap/bc/adt/programs/programs/ztest",
+			usages:    []string{"/sap/bc/adt/programs/programs/ZTEST#start=3,0"},
+			want:      nil,
+		},
+		{
+			name:      "object with the same prefix",
+			objectURI: "/sap/bc/adt/oo/classes/zcl_sales",
+			usages:    []string{"/sap/bc/adt/oo/classes/zcl_sales_helper"},
+			want:      []string{"/sap/bc/adt/oo/classes/zcl_sales_helper"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var usages []types.ADTUsageReference
+			for _, uri := range tt.usages {
+				usages = append(usages, usage(uri))
+			}
+			got := foreignUsages(tt.objectURI, usages)
+			if len(got) != len(tt.want) {
+				t.Fatalf("foreignUsages() returned %d usages, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Object.URI != tt.want[i] {
+					t.Errorf("usage %d = %s, want %s", i, got[i].Object.URI, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtVersionFeed maps the Atom feed returned by <source>/versions
+type adtVersionFeed struct {
+	XMLName xml.Name `xml:"feed"`
+	Entries []struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Author  string `xml:"author>name"`
+		Content struct {
+			Src string `xml:"src,attr"`
+		} `xml:"content"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Name string `xml:"name,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// GetVersions lists the stored versions of an object source, newest first. Pass a source
+// URI (e.g. a class include) to get the versions of a source other than the main source.
+func (c *ADTClientImpl) GetVersions(objectURI string) ([]types.ADTVersion, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if objectURI == "" {
+		return nil, fmt.Errorf("object URI required for version history")
+	}
+
+	sourceURI := sourceURIOf(objectURI)
+	c.logger.Info("Retrieving version history", zap.String("uri", sourceURI))
+
+	resp, body, err := c.doRequest("GET", c.absoluteURL(sourceURI+"/versions"), nil, map[string]string{
+		"Accept": "application/atom+xml;type=feed",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "no version history for %s (404)", sourceURI)
+		}
+		return nil, fmt.Errorf("failed to get version history: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var feed adtVersionFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse version history: %w", err)
+	}
+
+	versions := []types.ADTVersion{}
+	for _, entry := range feed.Entries {
+		version := types.ADTVersion{
+			ID:     strings.TrimSpace(entry.ID),
+			URI:    entry.Content.Src,
+			Author: entry.Author,
+			Date:   entry.Updated,
+			Title:  strings.TrimSpace(entry.Title),
+		}
+		for _, link := range entry.Links {
+			if !strings.Contains(link.Rel, "transport") {
+				continue
+			}
+			version.Transport = link.Name
+			if version.Transport == "" {
+				version.Transport = strings.ToUpper(link.Href[strings.LastIndex(link.Href, "/")+1:])
+			}
+		}
+		versions = append(versions, version)
+	}
+
+	c.logger.Info("Version history retrieved successfully", zap.Int("version_count", len(versions)))
+	return versions, nil
+}
+
+// GetVersionSource returns the source of a version. Besides the URIs returned by
+// GetVersions, source URIs with ?version=active or ?version=inactive are accepted.
+func (c *ADTClientImpl) GetVersionSource(versionURI string) (string, error) {
+	if !c.IsAuthenticated() {
+		return "", fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if versionURI == "" {
+		return "", fmt.Errorf("version URI required")
+	}
+
+	c.logger.Info("Retrieving version source", zap.String("uri", versionURI))
+
+	resp, body, err := c.doRequest("GET", c.absoluteURL(versionURI), nil, map[string]string{
+		"Accept": "text/plain",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return "", statusError(http.StatusNotFound, "version %s not found (404)", versionURI)
+		}
+		return "", fmt.Errorf("failed to get version source: HTTP %d - %s", resp.StatusCode, adtErrorMessage(body))
+	}
+
+	return string(body), nil
+}
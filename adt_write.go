@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// adtLockResponse maps the asx:abap document returned by ?_action=LOCK
+type adtLockResponse struct {
+	XMLName xml.Name `xml:"abap"`
+	Data    struct {
+		LockHandle string `xml:"LOCK_HANDLE"`
+		CorrNr     string `xml:"CORRNR"`
+		CorrUser   string `xml:"CORRUSER"`
+		CorrText   string `xml:"CORRTEXT"`
+		IsLocal    string `xml:"IS_LOCAL"`
+	} `xml:"values>DATA"`
+}
+
+// LockObject acquires a modify lock on an ADT object
+func (c *ADTClientImpl) LockObject(objectURI string) (*types.ADTLock, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Locking object", zap.String("uri", objectURI))
+
+	lockURL := c.absoluteURL(objectURI) + "?_action=LOCK&accessMode=MODIFY"
+	resp, body, err := c.doRequest("POST", lockURL, nil, map[string]string{
+		"Accept":                ADT_LOCK_ACCEPT,
+		ADT_SESSION_TYPE_HEADER: string(types.SessionStateful),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, statusError(http.StatusNotFound, "object %s not found (404)", objectURI)
+		} else if resp.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("lock failed (403) - %s", adtErrorMessage(body))
+		}
+		return nil, fmt.Errorf("failed to lock %s: HTTP %d - %s", objectURI, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	var lockResp adtLockResponse
+	if err := xml.Unmarshal(body, &lockResp); err != nil {
+		return nil, fmt.Errorf("failed to parse lock response: %w", err)
+	}
+	if lockResp.Data.LockHandle == "" {
+		return nil, fmt.Errorf("lock response for %s did not contain a lock handle", objectURI)
+	}
+
+	lock := &types.ADTLock{
+		LockHandle: lockResp.Data.LockHandle,
+		CorrNr:     lockResp.Data.CorrNr,
+		CorrUser:   lockResp.Data.CorrUser,
+		CorrText:   lockResp.Data.CorrText,
+		IsLocal:    lockResp.Data.IsLocal == "X",
+	}
+
+	c.logger.Info("Object locked successfully",
+		zap.String("uri", objectURI),
+		zap.String("corr_nr", lock.CorrNr),
+		zap.Bool("is_local", lock.IsLocal))
+
+	return lock, nil
+}
+
+// UnlockObject releases a lock previously acquired with LockObject
+func (c *ADTClientImpl) UnlockObject(objectURI, lockHandle string) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Unlocking object", zap.String("uri", objectURI))
+
+	unlockURL := c.absoluteURL(objectURI) + "?_action=UNLOCK&lockHandle=" + url.QueryEscape(lockHandle)
+	resp, body, err := c.doRequest("POST", unlockURL, nil, map[string]string{
+		ADT_SESSION_TYPE_HEADER: string(types.SessionStateful),
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to unlock %s: HTTP %d - %s", objectURI, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	c.logger.Info("Object unlocked successfully", zap.String("uri", objectURI))
+	return nil
+}
+
+// UpdateSource writes source code back to the system using lock/update/unlock.
+// The ETag of the given source is sent as If-Match so concurrent changes are detected.
+func (c *ADTClientImpl) UpdateSource(source *types.ADTSourceCode, transport string) (*types.ADTSourceCode, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+	if source == nil || source.URI == "" {
+		return nil, fmt.Errorf("source object URI required for update")
+	}
+
+	c.logger.Info("Updating source",
+		zap.String("uri", source.URI),
+		zap.String("transport", transport),
+		zap.Int("source_length", len(source.Source)))
+
+	lock, err := c.LockObject(source.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Always release the lock, even if the update fails
+	defer func() {
+		if unlockErr := c.UnlockObject(source.URI, lock.LockHandle); unlockErr != nil {
+			c.logger.Warn("Failed to release lock", zap.String("uri", source.URI), zap.Error(unlockErr))
+		}
+	}()
+
+	if transport == "" {
+		transport = lock.CorrNr
+	}
+
+	result, err := c.writeSource(source, lock.LockHandle, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Source updated successfully",
+		zap.String("uri", source.URI),
+		zap.String("transport", transport))
+
+	return result, nil
+}
+
+// writeSource PUTs the source of a locked object, recording the change in transport if given
+func (c *ADTClientImpl) writeSource(source *types.ADTSourceCode, lockHandle, transport string) (*types.ADTSourceCode, error) {
+	query := url.Values{"lockHandle": {lockHandle}}
+	if transport != "" {
+		query.Set("corrNr", transport)
+	}
+
+	headers := map[string]string{
+		"Content-Type":          "text/plain; charset=utf-8",
+		"Accept":                "text/plain",
+		ADT_SESSION_TYPE_HEADER: string(types.SessionStateful),
+	}
+	if source.ETag != "" {
+		headers["If-Match"] = source.ETag
+	}
+
+	sourceURI := source.SourceURI
+	if sourceURI == "" {
+		sourceURI = source.URI + "/source/main"
+	}
+
+	updateURL := c.absoluteURL(sourceURI) + "?" + query.Encode()
+	resp, body, err := c.doRequest("PUT", updateURL, strings.NewReader(source.Source), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		if resp.StatusCode == http.StatusPreconditionFailed {
+			return nil, fmt.Errorf("source of %s was changed on the server since it was read (412) - fetch it again", source.URI)
+		} else if resp.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("update forbidden (403) - %s", adtErrorMessage(body))
+		}
+		return nil, fmt.Errorf("failed to update %s: HTTP %d - %s", source.URI, resp.StatusCode, adtErrorMessage(body))
+	}
+
+	result := *source
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		result.ETag = etag
+		result.Version = etag
+	}
+
+	return &result, nil
+}
+
+// DeleteObject deletes an object using lock/delete. Failures are returned as *types.ADTDeleteError.
+func (c *ADTClientImpl) DeleteObject(objectURI, transport string) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("client not authenticated - call Authenticate() first")
+	}
+
+	c.logger.Info("Deleting object",
+		zap.String("uri", objectURI),
+		zap.String("transport", transport))
+
+	lock, err := c.LockObject(objectURI)
+	if err != nil {
+		kind := types.DeleteErrorLocked
+		if isNotFound(err) {
+			kind = types.DeleteErrorNotFound
+		}
+		return &types.ADTDeleteError{URI: objectURI, Kind: kind, Message: err.Error()}
+	}
+
+	if transport == "" {
+		transport = lock.CorrNr
+	}
+
+	query := url.Values{"lockHandle": {lock.LockHandle}}
+	if transport != "" {
+		query.Set("corrNr", transport)
+	}
+
+	resp, body, err := c.doRequest("DELETE", c.absoluteURL(objectURI)+"?"+query.Encode(), nil, map[string]string{
+		ADT_SESSION_TYPE_HEADER: string(types.SessionStateful),
+	})
+	if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent) {
+		c.logger.Info("Object deleted successfully", zap.String("uri", objectURI))
+		return nil
+	}
+
+	// The object still exists, so the lock has to be released
+	if unlockErr := c.UnlockObject(objectURI, lock.LockHandle); unlockErr != nil {
+		c.logger.Warn("Failed to release lock", zap.String("uri", objectURI), zap.Error(unlockErr))
+	}
+	if err != nil {
+		return err
+	}
+
+	deleteErr := &types.ADTDeleteError{
+		URI:     objectURI,
+		Kind:    deleteErrorKind(resp.StatusCode, body),
+		Message: adtErrorMessage(body),
+	}
+	// The message texts are language dependent, whether other objects still use the object
+	// is asked the system instead
+	if deleteErr.Kind == types.DeleteErrorForbidden || deleteErr.Kind == types.DeleteErrorFailed {
+		if usages, usageErr := c.WhereUsed(objectURI); usageErr == nil {
+			if usages = foreignUsages(objectURI, usages); len(usages) > 0 {
+				deleteErr.Kind = types.DeleteErrorDependency
+				deleteErr.Usages = usages
+			}
+		}
+	}
+	return deleteErr
+}
+
+// lockConflictMessages are the T100 messages of enqueue conflicts
+var lockConflictMessages = map[string]bool{
+	"MC/601": true, // object requested is currently locked by user
+}
+
+// deleteErrorKind classifies a failed delete response by status code and ADT exception.
+// Dependencies are not visible in the response and are checked separately.
+func deleteErrorKind(statusCode int, body []byte) string {
+	if statusCode == http.StatusNotFound {
+		return types.DeleteErrorNotFound
+	}
+	if exc := parseADTException(body); exc != nil {
+		if strings.Contains(exc.Type.ID, "Lock") || lockConflictMessages[exc.t100Key()] {
+			return types.DeleteErrorLocked
+		}
+	}
+	if statusCode == http.StatusConflict {
+		return types.DeleteErrorLocked
+	}
+	if statusCode == http.StatusForbidden {
+		return types.DeleteErrorForbidden
+	}
+	return types.DeleteErrorFailed
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bluefunda/abaper/types"
+)
+
+func adtExceptionBody(typeID, message, t100ID, t100No string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<exc:exception xmlns:exc="http://www.sap.com/abapxml/types/communicationframework">
+  <namespace id="com.sap.adt"/>
+  <type id="` + typeID + `"/>
+  <message lang="EN">` + message + `</message>
+  <properties>
+    <entry key="T100KEY-ID">` + t100ID + `</entry>
+    <entry key="T100KEY-NO">` + t100No + `</entry>
+  </properties>
+</exc:exception>`
+}
+
+func TestDeleteErrorKind(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       string
+	}{
+		{"not found", http.StatusNotFound, "", types.DeleteErrorNotFound},
+		{"enqueue conflict", http.StatusForbidden, adtExceptionBody("ExceptionResourceNoAccess", "Object is used by another user", "MC", "601"), types.DeleteErrorLocked},
+		{"lock exception type", http.StatusBadRequest, adtExceptionBody("ExceptionResourceLockedByAnotherUser", "Locked", "", ""), types.DeleteErrorLocked},
+		{"conflict status", http.StatusConflict, "", types.DeleteErrorLocked},
+		{"text mentioning use", http.StatusBadRequest, adtExceptionBody("ExceptionResourceFailure", "Variable is unused, caused by ...", "EU", "100"), types.DeleteErrorFailed},
+		{"forbidden", http.StatusForbidden, adtExceptionBody("ExceptionResourceNoAccess", "No authorization", "S#", "123"), types.DeleteErrorForbidden},
+		{"plain text body", http.StatusInternalServerError, "internal error", types.DeleteErrorFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deleteErrorKind(tt.statusCode, []byte(tt.body)); got != tt.want {
+				t.Errorf("deleteErrorKind() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
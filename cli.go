@@ -1,18 +1,55 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/bluefunda/abaper/diff"
+	"github.com/bluefunda/abaper/reports"
 	"github.com/bluefunda/abaper/types"
 )
 
 // CommandConfig holds command-specific configuration
 type CommandConfig struct {
-	Action     string // get, connect, search, list
-	ObjectType string // program, class, function, etc.
-	ObjectName string
-	Args       []string // Additional arguments
+	Action       string // get, put, connect, search, list
+	ObjectType   string // program, class, function, etc.
+	ObjectName   string
+	Args         []string // Additional arguments
+	FilePath     string   // Local source file (put, check) or directory (push)
+	Package      string   // Target package for new objects (push, create)
+	Description  string   // Description of new objects (create)
+	Transport    string   // Transport request for write operations
+	ETag         string   // Server version the local source is based on (put)
+	Format       string   // Report format (text, checkstyle, sarif, junit)
+	OutputPath   string   // Report output file, stdout if empty
+	Recursive    bool     // Walk subpackages (get package)
+	Details      bool     // Read the properties of every result (list packages)
+	Include      string   // Class include selector (get class)
+	Force        bool     // Skip the where-used check (delete)
+	AssumeYes    bool     // Do not ask for confirmation (delete)
+	ReleaseTasks bool     // Release open tasks before the request (transport release)
+	Depth        int      // Levels of nested usages (where-used)
+	MaxResults   int      // Search page size
+	Offset       int      // Search results to skip
+	Check        bool     // Only report unformatted files (fmt)
+	From         string   // Version to compare from (diff)
+	To           string   // Version to compare to (diff)
+	Left         string   // System to compare from (compare)
+	Right        string   // System to compare to (compare)
+	ShowDiff     bool     // Print diffs of differing objects (compare package)
+}
+
+// ATCOptions holds options for ATC runs
+type ATCOptions struct {
+	Variant        string
+	MaxVerdicts    int
+	FailOnPriority int // fail if a finding with this priority or higher exists, 0 disables
 }
 
 // normalizeObjectType normalizes object type strings
@@ -62,6 +99,9 @@ func HandleGet(config *CommandConfig, adtClient types.ADTClient, quiet bool, nor
 	case "PROGRAM":
 		source, err = adtClient.GetProgram(objectName)
 	case "CLASS":
+		if config.Include != "" {
+			return HandleGetClassIncludes(config, adtClient, quiet, normal)
+		}
 		source, err = adtClient.GetClass(objectName)
 	case "FUNCTION":
 		if len(config.Args) == 0 {
@@ -101,6 +141,41 @@ func HandleGet(config *CommandConfig, adtClient types.ADTClient, quiet bool, nor
 	return nil
 }
 
+// HandleGetClassIncludes retrieves one or all includes of a class
+func HandleGetClassIncludes(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	className := strings.ToUpper(config.ObjectName)
+
+	var includes []string
+	if !strings.EqualFold(config.Include, "all") {
+		includes = []string{strings.ToLower(config.Include)}
+	}
+
+	if !quiet || normal {
+		fmt.Printf("📄 Retrieving includes of class %s...\n", className)
+	}
+
+	result, err := adtClient.GetClassIncludes(className, includes)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve CLASS %s: %w", className, err)
+	}
+	if len(result) == 0 {
+		return fmt.Errorf("class %s has no %s include", className, config.Include)
+	}
+
+	for _, include := range result {
+		fmt.Printf("\n=== CLASS %s (%s) ===\n", className, include.Include)
+		if include.Source.Version != "" {
+			fmt.Printf("Version: %s\n", include.Source.Version)
+		}
+		fmt.Printf("\nSource Code:\n")
+		fmt.Println(strings.Repeat("=", 80))
+		fmt.Println(include.Source.Source)
+		fmt.Println(strings.Repeat("=", 80))
+	}
+
+	return nil
+}
+
 // HandleGetPackage retrieves package contents
 func HandleGetPackage(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
 	packageName := strings.ToUpper(config.ObjectName)
@@ -109,6 +184,17 @@ func HandleGetPackage(config *CommandConfig, adtClient types.ADTClient, quiet bo
 		fmt.Printf("📦 Retrieving package %s...\n", packageName)
 	}
 
+	if config.Recursive {
+		tree, err := adtClient.GetPackageTree(packageName)
+		if err != nil {
+			return fmt.Errorf("failed to get package tree: %w", err)
+		}
+
+		fmt.Printf("\n=== Package %s (recursive) ===\n", packageName)
+		printPackageTree(*tree, "")
+		return nil
+	}
+
 	packageInfo, err := adtClient.GetPackageContents(packageName)
 	if err != nil {
 		return fmt.Errorf("failed to get package: %w", err)
@@ -118,6 +204,9 @@ func HandleGetPackage(config *CommandConfig, adtClient types.ADTClient, quiet bo
 	fmt.Printf("Name: %s\n", packageInfo.Name)
 	fmt.Printf("Description: %s\n", packageInfo.Description)
 	fmt.Printf("Objects: %d\n", len(packageInfo.Objects))
+	if len(packageInfo.Subpackages) > 0 {
+		fmt.Printf("Subpackages: %s\n", strings.Join(packageInfo.Subpackages, ", "))
+	}
 
 	if len(packageInfo.Objects) > 0 {
 		fmt.Printf("\nObjects in Package:\n")
@@ -145,206 +234,1608 @@ func HandleGetPackage(config *CommandConfig, adtClient types.ADTClient, quiet bo
 	return nil
 }
 
-// HandleSearch searches for ABAP objects
-func HandleSearch(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
-	if config.ObjectType != "objects" {
-		return fmt.Errorf("search type must be 'objects': %s search objects <pattern>", "abaper")
+// printPackageTree prints a package node tree with box-drawing indentation
+func printPackageTree(node types.ADTNode, indent string) {
+	if indent == "" {
+		fmt.Printf("📦 %s", node.Name)
+		if node.Description != "" {
+			fmt.Printf(" - %s", node.Description)
+		}
+		fmt.Println()
+	}
+
+	for i, child := range node.Children {
+		branch, nextIndent := "├── ", indent+"│   "
+		if i == len(node.Children)-1 {
+			branch, nextIndent = "└── ", indent+"    "
+		}
+
+		icon := ""
+		if child.Type == types.ObjectTypePackage {
+			icon = "📦 "
+		}
+		fmt.Printf("%s%s%s%s (%s)", indent, branch, icon, child.Name, child.Type)
+		if child.Description != "" {
+			fmt.Printf(" - %s", child.Description)
+		}
+		fmt.Println()
+
+		if len(child.Children) > 0 {
+			printPackageTree(child, nextIndent)
+		}
+	}
+}
+
+// HandlePut writes a local source file back to an ABAP object
+func HandlePut(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for put action")
 	}
 	if config.ObjectName == "" {
-		return fmt.Errorf("search pattern required")
+		return fmt.Errorf("object name required for put action")
+	}
+	if config.FilePath == "" {
+		return fmt.Errorf("source file required: %s put TYPE NAME FILE", "abaper")
 	}
 
-	pattern := config.ObjectName
-	var objectTypes []string
-	for _, arg := range config.Args {
-		objectTypes = append(objectTypes, normalizeObjectType(arg))
+	objectType := normalizeObjectType(config.ObjectType)
+	objectName := strings.ToUpper(config.ObjectName)
+
+	newSource, err := os.ReadFile(config.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read source file: %w", err)
 	}
 
 	if !quiet || normal {
-		fmt.Printf("🔍 Searching for '%s'", pattern)
-		if len(objectTypes) > 0 {
-			fmt.Printf(" (types: %s)", strings.Join(objectTypes, ", "))
-		}
-		fmt.Println("...")
+		fmt.Printf("📤 Uploading %s to %s %s...\n", config.FilePath, objectType, objectName)
 	}
 
-	results, err := adtClient.SearchObjects(pattern, objectTypes)
+	// Fetch the current source to obtain the object URI
+	current, err := getObjectSource(config, adtClient)
 	if err != nil {
-		return fmt.Errorf("search failed: %w", err)
+		return fmt.Errorf("failed to retrieve %s %s: %w", objectType, objectName, err)
 	}
 
-	fmt.Printf("\n=== Search Results ===\n")
-	fmt.Printf("Pattern: %s\n", pattern)
-	fmt.Printf("Total Results: %d\n", results.Total)
-
-	if len(results.Objects) > 0 {
-		fmt.Printf("\nFound Objects:\n")
-		fmt.Println(strings.Repeat("=", 80))
+	if current.Source == string(newSource) {
+		fmt.Printf("✅ %s %s is unchanged - nothing to upload\n", objectType, objectName)
+		return nil
+	}
 
-		// Group results by type
-		resultsByType := make(map[string][]types.ADTObject)
-		for _, obj := range results.Objects {
-			resultsByType[obj.Type] = append(resultsByType[obj.Type], obj)
+	// The ETag just read always matches the server, concurrent changes are only detected
+	// against the version the local file was based on. Without it only --force overwrites.
+	switch {
+	case config.ETag != "":
+		if current.ETag != "" && current.ETag != config.ETag {
+			return fmt.Errorf("%s %s was changed on the server since version %s (now %s) - get it again and merge your changes",
+				objectType, objectName, config.ETag, current.ETag)
 		}
+		current.ETag = config.ETag
+	case !config.Force:
+		return fmt.Errorf("version of %s unknown - pass the Version printed by get with --etag, or use --force to overwrite %s %s",
+			config.FilePath, objectType, objectName)
+	}
 
-		for objectType, objects := range resultsByType {
-			fmt.Printf("\n%s (%d objects):\n", objectType, len(objects))
-			for _, obj := range objects {
-				fmt.Printf("  • %s", obj.Name)
-				if obj.Description != "" {
-					fmt.Printf(" - %s", obj.Description)
-				}
-				if obj.Package != "" {
-					fmt.Printf(" (Package: %s)", obj.Package)
-				}
-				fmt.Println()
-			}
-		}
-		fmt.Println(strings.Repeat("=", 80))
-	} else {
-		fmt.Printf("\nNo objects found matching pattern '%s'.\n", pattern)
+	current.Source = string(newSource)
+	updated, err := adtClient.UpdateSource(current, config.Transport)
+	if err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", objectType, objectName, err)
 	}
 
+	fmt.Printf("✅ %s %s updated (inactive - activate to make changes live)\n", objectType, objectName)
+	if updated.ETag != "" {
+		fmt.Printf("Version: %s\n", updated.ETag)
+	}
 	return nil
 }
 
-// HandleList lists objects (packages, etc.)
-func HandleList(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+// HandleCreate creates a new ABAP object and optionally uploads its initial source
+func HandleCreate(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
 	if config.ObjectType == "" {
-		return fmt.Errorf("list type required: %s list packages [pattern]", "abaper")
+		return fmt.Errorf("object type required for create action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for create action")
 	}
 
-	listType := strings.ToLower(config.ObjectType)
+	typeCode, err := types.ObjectTypeCode(config.ObjectType)
+	if err != nil {
+		return err
+	}
+	objectName := strings.ToUpper(config.ObjectName)
 
-	switch listType {
-	case "packages", "package":
-		return HandleListPackages(config, adtClient, quiet, normal)
-	default:
-		return fmt.Errorf("unsupported list type: %s", listType)
+	options := types.CreateOptions{
+		Description: config.Description,
+		Package:     config.Package,
+		Transport:   config.Transport,
 	}
-}
 
-// HandleListPackages lists packages
-func HandleListPackages(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
-	pattern := config.ObjectName
-	if pattern == "" {
-		pattern = "*"
+	// Function modules are created in their function group instead of a package
+	if typeCode == types.ObjectTypeFunction {
+		if len(config.Args) == 0 {
+			return fmt.Errorf("function group required for function: %s create function <n> <group>", "abaper")
+		}
+		options.FunctionGroup = config.Args[0]
+	} else if options.Package == "" {
+		return fmt.Errorf("package required: %s create TYPE NAME --package PACKAGE", "abaper")
+	}
+
+	obj, err := types.ObjectReference(typeCode, objectName, options.FunctionGroup)
+	if err != nil {
+		return err
+	}
+
+	var initialSource []byte
+	if config.FilePath != "" {
+		initialSource, err = os.ReadFile(config.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read source file: %w", err)
+		}
 	}
 
 	if !quiet || normal {
-		fmt.Printf("📦 Listing packages matching '%s'...\n", pattern)
+		fmt.Printf("🆕 Creating %s %s...\n", obj.Type, obj.Name)
 	}
 
-	packages, err := adtClient.ListPackages(pattern)
+	if err := adtClient.CreateObject(obj.Type, obj.Name, options); err != nil {
+		return fmt.Errorf("failed to create %s %s: %w", obj.Type, obj.Name, err)
+	}
+
+	if initialSource == nil {
+		fmt.Printf("✅ %s %s created\n", obj.Type, obj.Name)
+		return nil
+	}
+
+	if !quiet || normal {
+		fmt.Printf("📤 Uploading initial source from %s...\n", config.FilePath)
+	}
+
+	current, err := adtClient.GetSourceByURI(obj.URI + "/source/main")
 	if err != nil {
-		return fmt.Errorf("failed to list packages: %w", err)
+		return fmt.Errorf("%s %s created, but reading its source failed: %w", obj.Type, obj.Name, err)
+	}
+	current.Source = string(initialSource)
+	if _, err := adtClient.UpdateSource(current, config.Transport); err != nil {
+		return fmt.Errorf("%s %s created, but uploading the initial source failed: %w", obj.Type, obj.Name, err)
 	}
 
-	fmt.Printf("\n=== Packages ===\n")
-	fmt.Printf("Found %d packages:\n", len(packages))
-	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("✅ %s %s created with initial source (inactive - activate to make changes live)\n", obj.Type, obj.Name)
+	return nil
+}
 
-	for _, pkg := range packages {
-		fmt.Printf("• %s", pkg.Name)
-		if pkg.Description != "" {
-			fmt.Printf(" - %s", pkg.Description)
+// HandleDelete deletes an ABAP object after a where-used check and confirmation
+func HandleDelete(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for delete action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for delete action")
+	}
+
+	typeCode, err := types.ObjectTypeCode(config.ObjectType)
+	if err != nil {
+		return err
+	}
+
+	parent := ""
+	if typeCode == types.ObjectTypeFunction {
+		if len(config.Args) == 0 {
+			return fmt.Errorf("function group required for function: %s delete function <n> <group>", "abaper")
 		}
-		fmt.Println()
+		parent = config.Args[0]
+	}
+
+	obj, err := types.ObjectReference(typeCode, config.ObjectName, parent)
+	if err != nil {
+		return err
+	}
+
+	if !config.Force {
+		if !quiet || normal {
+			fmt.Printf("🔍 Checking where %s %s is used...\n", obj.Type, obj.Name)
+		}
+
+		usages, err := adtClient.WhereUsed(obj.URI)
+		if err != nil {
+			return fmt.Errorf("where-used check failed (use --force to skip it): %w", err)
+		}
+		if usages = foreignUsages(obj.URI, usages); len(usages) > 0 {
+			printUsages(usages)
+			return fmt.Errorf("%s %s is still used by %d object(s) - use --force to delete it anyway", obj.Type, obj.Name, len(usages))
+		}
+	}
+
+	if !config.AssumeYes {
+		fmt.Printf("Delete %s %s? [y/N] ", obj.Type, obj.Name)
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && answer == "" {
+			fmt.Println()
+			return fmt.Errorf("deletion of %s %s not confirmed (no input, use --yes in scripts)", obj.Type, obj.Name)
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("deletion of %s %s aborted", obj.Type, obj.Name)
+		}
+	}
+
+	if err := adtClient.DeleteObject(obj.URI, config.Transport); err != nil {
+		var deleteErr *types.ADTDeleteError
+		if errors.As(err, &deleteErr) && len(deleteErr.Usages) > 0 {
+			printUsages(deleteErr.Usages)
+		}
+		return fmt.Errorf("failed to delete %s %s: %w", obj.Type, obj.Name, err)
 	}
 
+	fmt.Printf("✅ %s %s deleted\n", obj.Type, obj.Name)
 	return nil
 }
 
-// HandleConnect tests ADT connection
-func HandleConnect(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
-	if !quiet || normal {
-		fmt.Println("🔌 Testing ADT connection...")
+// printUsages lists the objects using another object
+func printUsages(usages []types.ADTUsageReference) {
+	fmt.Printf("Used by %d object(s):\n", len(usages))
+	for _, usage := range usages {
+		fmt.Printf("  • %s (%s)", usage.Object.Name, usage.Object.Type)
+		if usage.Object.Package != "" {
+			fmt.Printf(" in %s", usage.Object.Package)
+		}
+		fmt.Println()
 	}
+}
 
-	if adtClient == nil {
-		return fmt.Errorf("ADT client not configured")
+// HandleActivate activates one or more ABAP objects
+func HandleActivate(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for activate action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for activate action")
 	}
 
-	if err := adtClient.TestConnection(); err != nil {
-		fmt.Printf("❌ Connection failed: %v\n", err)
-		fmt.Println("\n💡 Troubleshooting tips:")
-		fmt.Println("  1. Check your SAP credentials and host configuration")
-		fmt.Println("  2. Verify ADT services are activated in SICF (transaction SICF)")
-		fmt.Println("  3. Ensure your user has S_DEVELOP authorization")
-		fmt.Println("  4. Test basic connectivity: ping <host>")
+	// Resolved by type code: normalizeObjectType maps function groups to function modules
+	objectType := strings.ToUpper(config.ObjectType)
+	typeCode, err := types.ObjectTypeCode(objectType)
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("✅ ADT connection successful!")
+	var objects []types.ADTObject
+	if typeCode == types.ObjectTypeFunction {
+		// Function modules take their function group as the only extra argument
+		if len(config.Args) == 0 {
+			return fmt.Errorf("function group required for function: %s activate function <n> <group>", "abaper")
+		}
+		obj, err := types.ObjectReference(objectType, config.ObjectName, config.Args[0])
+		if err != nil {
+			return err
+		}
+		objects = append(objects, obj)
+	} else {
+		for _, name := range append([]string{config.ObjectName}, config.Args...) {
+			obj, err := types.ObjectReference(objectType, name, "")
+			if err != nil {
+				return err
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	if !quiet || normal {
+		fmt.Printf("⚡ Activating %d object(s)...\n", len(objects))
+	}
+
+	result, err := adtClient.Activate(objects)
+	if err != nil {
+		return fmt.Errorf("activation failed: %w", err)
+	}
+
+	printActivationResult(result)
+
+	if !result.Success {
+		return fmt.Errorf("activation failed")
+	}
 	return nil
 }
 
-// getObjectSource retrieves source code for any supported object type
-func getObjectSource(config *CommandConfig, adtClient types.ADTClient) (*types.ADTSourceCode, error) {
-	objectType := normalizeObjectType(config.ObjectType)
-	objectName := strings.ToUpper(config.ObjectName)
+// printActivationResult prints activation messages and dependent inactive objects
+func printActivationResult(result *types.ADTActivationResult) {
+	errors := 0
+	for _, msg := range result.Messages {
+		icon := "ℹ️"
+		switch msg.Severity {
+		case "error":
+			icon = "❌"
+			errors++
+		case "warning":
+			icon = "⚠️"
+		}
+		fmt.Printf("%s %s", icon, msg.ObjectDescription)
+		if msg.Line > 0 {
+			fmt.Printf(" (line %d", msg.Line)
+			if msg.Column > 0 {
+				fmt.Printf(", column %d", msg.Column)
+			}
+			fmt.Printf(")")
+		}
+		fmt.Printf(": %s\n", msg.Text)
+	}
 
-	switch objectType {
-	case "PROGRAM":
-		return adtClient.GetProgram(objectName)
-	case "CLASS":
-		return adtClient.GetClass(objectName)
-	case "FUNCTION":
-		if len(config.Args) == 0 {
-			return nil, fmt.Errorf("function group required for function")
+	if len(result.Inactive) > 0 {
+		fmt.Println("\nThe following dependent objects are inactive and must be activated together:")
+		for _, obj := range result.Inactive {
+			fmt.Printf("  • %s (%s)\n", obj.Name, obj.Type)
 		}
-		functionGroup := strings.ToUpper(config.Args[0])
-		return adtClient.GetFunction(objectName, functionGroup)
-	case "INCLUDE":
-		return adtClient.GetInclude(objectName)
-	case "INTERFACE":
-		return adtClient.GetInterface(objectName)
-	case "STRUCTURE":
-		return adtClient.GetStructure(objectName)
-	case "TABLE":
-		return adtClient.GetTable(objectName)
-	default:
-		return nil, fmt.Errorf("unsupported object type for source retrieval: %s", objectType)
+	}
+
+	if result.Success {
+		fmt.Println("✅ Activation successful")
+	} else {
+		fmt.Printf("❌ Activation failed (%d error(s))\n", errors)
 	}
 }
 
-// CreateADTClient creates ADT client from configuration
-func CreateADTClient(config *Config) (types.ADTClient, error) {
-	if config.ADTHost == "" {
-		return nil, fmt.Errorf("ADT host not configured (use --adt-host or set SAP_HOST)")
+// HandleCheck runs a server-side syntax check on an object or a local file
+func HandleCheck(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for check action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for check action")
 	}
 
-	if config.ADTUsername == "" {
-		return nil, fmt.Errorf("ADT username not configured (use --adt-username or set SAP_USERNAME)")
+	objectType := strings.ToUpper(config.ObjectType)
+	functionGroup := ""
+	if len(config.Args) > 0 {
+		functionGroup = config.Args[0]
 	}
 
-	if config.ADTPassword == "" {
-		return nil, fmt.Errorf("ADT password not configured (use --adt-password or set SAP_PASSWORD)")
+	obj, err := types.ObjectReference(objectType, config.ObjectName, functionGroup)
+	if err != nil {
+		return err
 	}
 
-	adtConfig := &types.ADTConfig{
-		Host:     config.ADTHost,
-		Client:   config.ADTClient,
-		Username: config.ADTUsername,
-		Password: config.ADTPassword,
-		Language: "EN",
-		// Enable SSL handling for port 50000
-		AllowSelfSigned: true,
-		ConnectTimeout:  30,
-		RequestTimeout:  120,
-		Debug:           false,
+	var source string
+	if config.FilePath != "" {
+		content, err := os.ReadFile(config.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read source file: %w", err)
+		}
+		source = string(content)
 	}
 
-	// Set default client if not specified
-	if adtConfig.Client == "" {
-		adtConfig.Client = "100"
+	if !quiet || normal {
+		fmt.Printf("🔎 Checking %s %s...\n", objectType, obj.Name)
 	}
 
-	client := NewADTClient(adtConfig)
+	result, err := adtClient.SyntaxCheck(obj.URI, source)
+	if err != nil {
+		return fmt.Errorf("syntax check failed: %w", err)
+	}
 
-	// Force stateful session BEFORE authentication
+	// Compiler-style output so editors and hooks can parse the findings
+	location := obj.Name
+	if config.FilePath != "" {
+		location = config.FilePath
+	}
+	for _, msg := range result.Messages {
+		fmt.Printf("%s:%d:%d: %s: %s\n", location, msg.Line, msg.Column, msg.Severity, msg.Text)
+	}
+
+	if result.HasErrors {
+		return fmt.Errorf("syntax check found errors in %s %s", objectType, obj.Name)
+	}
+
+	if !quiet || normal || len(result.Messages) == 0 {
+		fmt.Printf("✅ %s %s has no syntax errors\n", objectType, obj.Name)
+	}
+	return nil
+}
+
+// HandleFmt formats local ABAP files with the pretty printer of the system.
+// With Check set the files are only reported, which fails the command if any needs formatting.
+func HandleFmt(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	files, err := collectABAPFiles(config.Args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no ABAP files found in %s", strings.Join(config.Args, ", "))
+	}
+
+	if !quiet || normal {
+		if settings, err := adtClient.GetFormatterSettings(); err == nil {
+			fmt.Printf("🎨 Formatting %d file(s) (keyword case: %s, indentation: %t)...\n", len(files), settings.Style, settings.Indentation)
+		} else {
+			fmt.Printf("🎨 Formatting %d file(s)...\n", len(files))
+		}
+	}
+
+	var unformatted, failed int
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		source := string(content)
+
+		formatted, err := adtClient.Format(source)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		formatted = matchSourceLayout(source, formatted)
+		if formatted == source {
+			continue
+		}
+		unformatted++
+
+		// Check mode lists the files like gofmt -l
+		if config.Check {
+			fmt.Println(file)
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(formatted), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		if !quiet || normal {
+			fmt.Printf("✏️  %s\n", file)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) could not be formatted", failed)
+	}
+	if config.Check && unformatted > 0 {
+		return fmt.Errorf("%d of %d file(s) are not formatted", unformatted, len(files))
+	}
+
+	if !quiet || normal {
+		if config.Check {
+			fmt.Printf("✅ All %d file(s) are formatted\n", len(files))
+		} else {
+			fmt.Printf("✅ %d of %d file(s) reformatted\n", unformatted, len(files))
+		}
+	}
+	return nil
+}
+
+// collectABAPFiles expands directories to the .abap files they contain, skipping hidden directories
+func collectABAPFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if file != path && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(entry.Name(), ".abap") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// matchSourceLayout applies the line endings and final newline of the original source to the formatted one
+func matchSourceLayout(original, formatted string) string {
+	formatted = strings.TrimRight(strings.ReplaceAll(formatted, "\r\n", "\n"), "\n")
+	if strings.HasSuffix(original, "\n") {
+		formatted += "\n"
+	}
+	if strings.Contains(original, "\r\n") {
+		formatted = strings.ReplaceAll(formatted, "\n", "\r\n")
+	}
+	return formatted
+}
+
+// HandleATC runs the ABAP Test Cockpit for an object or package
+func HandleATC(config *CommandConfig, options *ATCOptions, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for atc action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for atc action")
+	}
+
+	objectType := strings.ToUpper(config.ObjectType)
+	functionGroup := ""
+	if len(config.Args) > 0 {
+		functionGroup = config.Args[0]
+	}
+
+	obj, err := types.ObjectReference(objectType, config.ObjectName, functionGroup)
+	if err != nil {
+		return err
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🧪 Running ATC for %s %s...\n", objectType, obj.Name)
+	}
+
+	result, err := adtClient.RunATC([]types.ADTObject{obj}, options.Variant, options.MaxVerdicts)
+	if err != nil {
+		return fmt.Errorf("ATC run failed: %w", err)
+	}
+
+	format := strings.ToLower(config.Format)
+	if format == "" || format == reports.FormatText {
+		printATCResult(result)
+	} else {
+		var out io.Writer = os.Stdout
+		if config.OutputPath != "" {
+			file, err := os.Create(config.OutputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create report file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := reports.WriteATC(out, result, format); err != nil {
+			return err
+		}
+		if config.OutputPath != "" && (!quiet || normal) {
+			fmt.Printf("📄 ATC report written to %s\n", config.OutputPath)
+		}
+	}
+
+	if options.FailOnPriority > 0 {
+		blocking := 0
+		for _, finding := range result.Findings {
+			if finding.Priority > 0 && finding.Priority <= options.FailOnPriority {
+				blocking++
+			}
+		}
+		if blocking > 0 {
+			return fmt.Errorf("ATC found %d finding(s) with priority %d or higher", blocking, options.FailOnPriority)
+		}
+	}
+
+	return nil
+}
+
+// printATCResult prints ATC findings grouped by object
+func printATCResult(result *types.ADTATCResult) {
+	fmt.Printf("\n=== ATC Results ===\n")
+	fmt.Printf("Variant: %s\n", result.Variant)
+	fmt.Printf("Findings: %d\n", len(result.Findings))
+
+	if len(result.Findings) == 0 {
+		fmt.Println("✅ No ATC findings")
+		return
+	}
+
+	fmt.Println(strings.Repeat("=", 80))
+	names, grouped := reports.ATCFindingsByObject(result)
+	for _, name := range names {
+		findings := grouped[name]
+		fmt.Printf("\n%s (%s, %d findings):\n", name, findings[0].ObjectType, len(findings))
+		for _, finding := range findings {
+			fmt.Printf("  [P%d] line %d: %s (%s)\n", finding.Priority, finding.Line, finding.MessageTitle, finding.CheckTitle)
+		}
+	}
+	fmt.Println(strings.Repeat("=", 80))
+}
+
+// HandleTest runs ABAP Unit tests for an object or package
+func HandleTest(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for test action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for test action")
+	}
+
+	objectType := strings.ToUpper(config.ObjectType)
+	functionGroup := ""
+	if len(config.Args) > 0 {
+		functionGroup = config.Args[0]
+	}
+
+	obj, err := types.ObjectReference(objectType, config.ObjectName, functionGroup)
+	if err != nil {
+		return err
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🧪 Running ABAP Unit tests for %s %s...\n", objectType, obj.Name)
+	}
+
+	result, err := adtClient.RunUnitTests([]types.ADTObject{obj})
+	if err != nil {
+		return fmt.Errorf("ABAP Unit run failed: %w", err)
+	}
+
+	format := strings.ToLower(config.Format)
+	switch format {
+	case "", reports.FormatText:
+		printUnitResult(result)
+	case reports.FormatJUnit:
+		var out io.Writer = os.Stdout
+		if config.OutputPath != "" {
+			file, err := os.Create(config.OutputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create report file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := reports.WriteUnitJUnit(out, result); err != nil {
+			return err
+		}
+		if config.OutputPath != "" && (!quiet || normal) {
+			fmt.Printf("📄 JUnit report written to %s\n", config.OutputPath)
+		}
+	default:
+		return fmt.Errorf("unsupported report format: %s (use text or junit)", config.Format)
+	}
+
+	if result.Failures > 0 || result.Errors > 0 {
+		return fmt.Errorf("ABAP Unit: %d failure(s), %d error(s) in %d test(s)", result.Failures, result.Errors, result.Tests)
+	}
+	return nil
+}
+
+// printUnitResult prints ABAP Unit results per test class and method
+func printUnitResult(result *types.ADTUnitRunResult) {
+	fmt.Printf("\n=== ABAP Unit Results ===\n")
+
+	for _, program := range result.Programs {
+		for _, alert := range program.Alerts {
+			fmt.Printf("\n%s\n  ⚠️ %s\n", program.Name, alert.Title)
+		}
+		for _, class := range program.Classes {
+			fmt.Printf("\n%s => %s\n", program.Name, class.Name)
+			for _, alert := range class.Alerts {
+				fmt.Printf("  ⚠️ %s\n", alert.Title)
+			}
+			for _, method := range class.Methods {
+				icon := "✅"
+				if outcome, _ := types.UnitOutcome(method.Alerts); outcome != types.UnitPassed {
+					icon = "❌"
+				}
+				fmt.Printf("  %s %s (%.3fs)\n", icon, method.Name, method.ExecutionTime)
+				for _, alert := range method.Alerts {
+					fmt.Printf("      %s\n", alert.Title)
+					for _, detail := range alert.Details {
+						fmt.Printf("        %s\n", detail)
+					}
+					for _, entry := range alert.Stack {
+						fmt.Printf("        at %s %s\n", entry.Name, entry.Description)
+					}
+				}
+			}
+		}
+	}
+
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Printf("Tests: %d, Failures: %d, Errors: %d\n", result.Tests, result.Failures, result.Errors)
+}
+
+// HandleSearch searches for ABAP objects
+func HandleSearch(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType != "objects" {
+		return fmt.Errorf("search type must be 'objects': %s search objects <pattern>", "abaper")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("search pattern required")
+	}
+
+	pattern := config.ObjectName
+	var objectTypes []string
+	for _, arg := range config.Args {
+		objectTypes = append(objectTypes, strings.ToUpper(arg))
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🔍 Searching for '%s'", pattern)
+		if len(objectTypes) > 0 {
+			fmt.Printf(" (types: %s)", strings.Join(objectTypes, ", "))
+		}
+		fmt.Println("...")
+	}
+
+	results, err := adtClient.SearchObjectsWithOptions(pattern, types.SearchOptions{
+		ObjectTypes: objectTypes,
+		MaxResults:  config.MaxResults,
+		Offset:      config.Offset,
+	})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	fmt.Printf("\n=== Search Results ===\n")
+	fmt.Printf("Pattern: %s\n", pattern)
+	if results.Total > 0 || !results.HasMore {
+		fmt.Printf("Total Results: %d\n", results.Total)
+	} else {
+		fmt.Printf("Total Results: more than %d\n", results.Offset+len(results.Objects))
+	}
+	if len(results.Objects) > 0 && (results.Offset > 0 || results.HasMore) {
+		fmt.Printf("Showing: %d-%d\n", results.Offset+1, results.Offset+len(results.Objects))
+	}
+
+	if len(results.Objects) > 0 {
+		fmt.Printf("\nFound Objects:\n")
+		fmt.Println(strings.Repeat("=", 80))
+
+		// Group results by type
+		resultsByType := make(map[string][]types.ADTObject)
+		for _, obj := range results.Objects {
+			resultsByType[obj.Type] = append(resultsByType[obj.Type], obj)
+		}
+
+		for objectType, objects := range resultsByType {
+			fmt.Printf("\n%s (%d objects):\n", objectType, len(objects))
+			for _, obj := range objects {
+				fmt.Printf("  • %s", obj.Name)
+				if obj.Description != "" {
+					fmt.Printf(" - %s", obj.Description)
+				}
+				if obj.Package != "" {
+					fmt.Printf(" (Package: %s)", obj.Package)
+				}
+				fmt.Println()
+			}
+		}
+		fmt.Println(strings.Repeat("=", 80))
+		if results.HasMore {
+			fmt.Printf("More results available - use --offset %d to see the next page\n", results.Offset+len(results.Objects))
+		}
+	} else {
+		fmt.Printf("\nNo objects found matching pattern '%s'.\n", pattern)
+	}
+
+	return nil
+}
+
+// HandleList lists objects (packages, etc.)
+func HandleList(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("list type required: %s list packages [pattern]", "abaper")
+	}
+
+	listType := strings.ToLower(config.ObjectType)
+
+	switch listType {
+	case "packages", "package":
+		return HandleListPackages(config, adtClient, quiet, normal)
+	case "inactive":
+		return HandleListInactive(config, adtClient, quiet, normal)
+	default:
+		return fmt.Errorf("unsupported list type: %s", listType)
+	}
+}
+
+// HandleListPackages lists packages
+func HandleListPackages(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	pattern := config.ObjectName
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	if !quiet || normal {
+		fmt.Printf("📦 Listing packages matching '%s'...\n", pattern)
+	}
+
+	packages, err := adtClient.ListPackages(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to list packages: %w", err)
+	}
+
+	// The search result has no component assignments, they cost one request per package
+	if config.Details {
+		for i := range packages {
+			info, err := adtClient.GetPackageInfo(packages[i].Name)
+			if err != nil {
+				return fmt.Errorf("failed to read package %s: %w", packages[i].Name, err)
+			}
+			if info.Description != "" {
+				packages[i].Description = info.Description
+			}
+			if info.ParentPackage != "" {
+				packages[i].ParentPackage = info.ParentPackage
+			}
+			packages[i].SoftwareComponent = info.SoftwareComponent
+			packages[i].ApplicationComponent = info.ApplicationComponent
+		}
+	}
+
+	fmt.Printf("\n=== Packages ===\n")
+	fmt.Printf("Found %d packages:\n", len(packages))
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, pkg := range packages {
+		fmt.Printf("• %s", pkg.Name)
+		if pkg.Description != "" {
+			fmt.Printf(" - %s", pkg.Description)
+		}
+		fmt.Println()
+
+		var details []string
+		if pkg.ParentPackage != "" {
+			details = append(details, "Parent: "+pkg.ParentPackage)
+		}
+		if pkg.SoftwareComponent != "" {
+			details = append(details, "Software Component: "+pkg.SoftwareComponent)
+		}
+		if pkg.ApplicationComponent != "" {
+			details = append(details, "Application Component: "+pkg.ApplicationComponent)
+		}
+		if len(details) > 0 {
+			fmt.Printf("    %s\n", strings.Join(details, ", "))
+		}
+	}
+
+	return nil
+}
+
+// HandleListInactive lists the current user's inactive objects
+func HandleListInactive(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if !quiet || normal {
+		fmt.Println("💤 Listing inactive objects...")
+	}
+
+	objects, err := adtClient.GetInactiveObjects()
+	if err != nil {
+		return fmt.Errorf("failed to list inactive objects: %w", err)
+	}
+
+	fmt.Printf("\n=== Inactive Objects ===\n")
+	fmt.Printf("Found %d inactive objects:\n", len(objects))
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, item := range objects {
+		fmt.Printf("• %s (%s)", item.Object.Name, item.Object.Type)
+		if item.User != "" {
+			fmt.Printf(" - %s", item.User)
+		}
+		if item.Transport != "" {
+			fmt.Printf(" [%s]", item.Transport)
+		}
+		if item.Deleted {
+			fmt.Printf(" (deleted)")
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// HandleWhereUsed prints the objects using an object as a tree
+func HandleWhereUsed(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for where-used action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for where-used action")
+	}
+
+	parent := ""
+	if len(config.Args) > 0 {
+		parent = config.Args[0]
+	}
+	obj, err := types.ObjectReference(config.ObjectType, config.ObjectName, parent)
+	if err != nil {
+		return err
+	}
+
+	depth := config.Depth
+	if depth < 1 {
+		depth = 1
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🔍 Finding usages of %s %s (depth %d)...\n", obj.Type, obj.Name, depth)
+	}
+
+	visited := map[string]bool{strings.ToLower(obj.URI): true}
+	usages, err := collectUsages(adtClient, obj.URI, depth, visited)
+	if err != nil {
+		return fmt.Errorf("where-used failed for %s %s: %w", obj.Type, obj.Name, err)
+	}
+
+	fmt.Printf("\n=== Where-Used %s %s ===\n", obj.Type, obj.Name)
+	fmt.Printf("🔗 %s (%s)\n", obj.Name, obj.Type)
+	if len(usages) == 0 {
+		fmt.Println("└── (no usages found)")
+		return nil
+	}
+	printUsageTree(usages, "")
+
+	return nil
+}
+
+// collectUsages retrieves usages and, up to depth levels, the usages of the using objects
+func collectUsages(adtClient types.ADTClient, objectURI string, depth int, visited map[string]bool) ([]types.ADTUsageReference, error) {
+	usages, err := adtClient.WhereUsed(objectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if depth > 1 {
+		for i := range usages {
+			key := strings.ToLower(usages[i].Object.URI)
+			if usages[i].Object.URI == "" || visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			nested, err := collectUsages(adtClient, usages[i].Object.URI, depth-1, visited)
+			if err != nil {
+				return nil, err
+			}
+			usages[i].Usages = nested
+		}
+	}
+
+	return usages, nil
+}
+
+// printUsageTree prints usage references with their snippets using box-drawing indentation
+func printUsageTree(usages []types.ADTUsageReference, indent string) {
+	for i, usage := range usages {
+		branch, nextIndent := "├── ", indent+"│   "
+		if i == len(usages)-1 {
+			branch, nextIndent = "└── ", indent+"    "
+		}
+
+		fmt.Printf("%s%s%s (%s)", indent, branch, usage.Object.Name, usage.Object.Type)
+		if usage.Object.Package != "" {
+			fmt.Printf(" [%s]", usage.Object.Package)
+		}
+		if usage.Object.Description != "" {
+			fmt.Printf(" - %s", usage.Object.Description)
+		}
+		fmt.Println()
+
+		for _, snippet := range usage.Snippets {
+			if snippet.Line > 0 {
+				fmt.Printf("%s    line %d: %s\n", nextIndent, snippet.Line, snippet.Content)
+			} else {
+				fmt.Printf("%s    %s\n", nextIndent, snippet.Content)
+			}
+		}
+
+		if len(usage.Usages) > 0 {
+			printUsageTree(usage.Usages, nextIndent)
+		}
+	}
+}
+
+// HandleNav resolves the symbol at a source position to its definition
+func HandleNav(config *CommandConfig, line, column int, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" {
+		return fmt.Errorf("object type required for nav action")
+	}
+	if config.ObjectName == "" {
+		return fmt.Errorf("object name required for nav action")
+	}
+
+	parent := ""
+	if len(config.Args) > 0 {
+		parent = config.Args[0]
+	}
+	obj, err := types.ObjectReference(config.ObjectType, config.ObjectName, parent)
+	if err != nil {
+		return err
+	}
+
+	source := ""
+	if config.FilePath != "" {
+		content, err := os.ReadFile(config.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read source file: %w", err)
+		}
+		source = string(content)
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🧭 Resolving %s %s at %d:%d...\n", obj.Type, obj.Name, line, column)
+	}
+
+	definition, err := adtClient.FindDefinition(obj.URI, source, line, column)
+	if err != nil {
+		return fmt.Errorf("navigation failed: %w", err)
+	}
+
+	fmt.Printf("\n=== Definition ===\n")
+	fmt.Printf("Object: %s (%s)\n", definition.Object.Name, definition.Object.Type)
+	fmt.Printf("Location: %s:%d:%d\n", definition.URI, definition.Line, definition.Column)
+
+	// Show the defining line when the target source can be read
+	if definition.Line > 0 {
+		if target, err := adtClient.GetSourceByURI(definition.URI); err == nil {
+			lines := strings.Split(target.Source, "\n")
+			if definition.Line <= len(lines) {
+				fmt.Printf("\n%5d | %s\n", definition.Line, strings.TrimRight(lines[definition.Line-1], "\r"))
+			}
+		}
+	}
+
+	return nil
+}
+
+// versionSourceURI resolves the object and source URI for history and diff, honouring the class include selector
+func versionSourceURI(config *CommandConfig) (types.ADTObject, string, error) {
+	parent := ""
+	if len(config.Args) > 0 {
+		parent = config.Args[0]
+	}
+	obj, err := types.ObjectReference(config.ObjectType, config.ObjectName, parent)
+	if err != nil {
+		return obj, "", err
+	}
+	if config.Include == "" {
+		return obj, obj.URI + "/source/main", nil
+	}
+	if obj.Type != types.ObjectTypeClass {
+		return obj, "", fmt.Errorf("--include is only supported for classes")
+	}
+	sourceURI, err := types.ClassIncludeURI(obj.Name, config.Include)
+	return obj, sourceURI, err
+}
+
+// formatVersionDate shortens an Atom timestamp for display
+func formatVersionDate(date string) string {
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t.Local().Format("2006-01-02 15:04")
+	}
+	return date
+}
+
+// HandleHistory lists the stored versions of an object source
+func HandleHistory(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" || config.ObjectName == "" {
+		return fmt.Errorf("object type and name required for history action")
+	}
+
+	obj, sourceURI, err := versionSourceURI(config)
+	if err != nil {
+		return err
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🕒 Retrieving version history of %s %s...\n", obj.Type, obj.Name)
+	}
+
+	versions, err := adtClient.GetVersions(sourceURI)
+	if err != nil {
+		return fmt.Errorf("failed to get version history: %w", err)
+	}
+
+	fmt.Printf("\n=== Version History %s %s ===\n", obj.Type, obj.Name)
+	fmt.Printf("Found %d versions:\n", len(versions))
+	fmt.Println(strings.Repeat("=", 50))
+
+	fmt.Printf("%-8s %-16s %-12s %-12s %s\n", "ID", "DATE", "AUTHOR", "TRANSPORT", "TITLE")
+	for _, version := range versions {
+		fmt.Printf("%-8s %-16s %-12s %-12s %s\n", version.ID, formatVersionDate(version.Date), version.Author, version.Transport, version.Title)
+	}
+
+	return nil
+}
+
+// isSourceState reports whether a version refers to the current active or inactive source
+func isSourceState(spec string) bool {
+	spec = strings.ToLower(spec)
+	return spec == "active" || spec == "inactive"
+}
+
+// resolveVersion maps a version given on the command line to its content URI and a label.
+// A version is active, inactive, an ID from the history or a transport request number.
+func resolveVersion(spec, sourceURI string, versions []types.ADTVersion) (string, string, error) {
+	if isSourceState(spec) {
+		return sourceURI + "?version=" + strings.ToLower(spec), strings.ToLower(spec), nil
+	}
+
+	for _, version := range versions {
+		if version.ID == spec {
+			return version.URI, version.ID, nil
+		}
+	}
+	// Versions are listed newest first, so a transport resolves to its latest version
+	for _, version := range versions {
+		if version.Transport != "" && strings.EqualFold(version.Transport, spec) {
+			return version.URI, version.ID + ", " + version.Transport, nil
+		}
+	}
+	return "", "", fmt.Errorf("version %s not found (use active, inactive, a version ID or a transport from history)", spec)
+}
+
+// HandleDiff prints a unified diff between two versions of an object source
+func HandleDiff(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" || config.ObjectName == "" {
+		return fmt.Errorf("object type and name required for diff action")
+	}
+	if config.From == "" {
+		return fmt.Errorf("version to compare from required: %s diff TYPE NAME --from VERSION [--to VERSION]", "abaper")
+	}
+	to := config.To
+	if to == "" {
+		to = "active"
+	}
+
+	obj, sourceURI, err := versionSourceURI(config)
+	if err != nil {
+		return err
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🔀 Comparing %s %s %s with %s...\n", obj.Type, obj.Name, config.From, to)
+	}
+
+	// The history is only needed for stored versions
+	var versions []types.ADTVersion
+	if !isSourceState(config.From) || !isSourceState(to) {
+		if versions, err = adtClient.GetVersions(sourceURI); err != nil {
+			return fmt.Errorf("failed to get version history: %w", err)
+		}
+	}
+
+	fromURI, fromLabel, err := resolveVersion(config.From, sourceURI, versions)
+	if err != nil {
+		return err
+	}
+	toURI, toLabel, err := resolveVersion(to, sourceURI, versions)
+	if err != nil {
+		return err
+	}
+
+	fromSource, err := adtClient.GetVersionSource(fromURI)
+	if err != nil {
+		return fmt.Errorf("failed to get version %s: %w", config.From, err)
+	}
+	toSource, err := adtClient.GetVersionSource(toURI)
+	if err != nil {
+		return fmt.Errorf("failed to get version %s: %w", to, err)
+	}
+
+	unified := diff.Unified(
+		fmt.Sprintf("%s (%s)", obj.Name, fromLabel),
+		fmt.Sprintf("%s (%s)", obj.Name, toLabel),
+		fromSource, toSource, diff.DefaultContext)
+	if unified == "" {
+		if !quiet || normal {
+			fmt.Println("✅ No differences")
+		}
+		return nil
+	}
+
+	fmt.Print(unified)
+	return nil
+}
+
+// HandleTransport dispatches transport request subcommands
+func HandleTransport(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	switch strings.ToLower(config.ObjectType) {
+	case "list":
+		return HandleTransportList(config, adtClient, quiet, normal)
+	case "objects":
+		return HandleTransportObjects(config, adtClient, quiet, normal)
+	case "create":
+		return HandleTransportCreate(config, adtClient, quiet, normal)
+	case "check":
+		return HandleTransportCheck(config, adtClient, quiet, normal)
+	case "assign":
+		return HandleTransportAssign(config, adtClient, quiet, normal)
+	case "release":
+		return HandleTransportRelease(config, adtClient, quiet, normal)
+	case "":
+		return fmt.Errorf("transport action required: %s transport list|create|release|objects|check|assign", "abaper")
+	default:
+		return fmt.Errorf("unsupported transport action: %s", config.ObjectType)
+	}
+}
+
+// HandleTransportList lists the modifiable transport requests of the current user
+func HandleTransportList(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if !quiet || normal {
+		fmt.Println("🚚 Listing transport requests...")
+	}
+
+	transports, err := adtClient.GetTransports()
+	if err != nil {
+		return fmt.Errorf("failed to list transport requests: %w", err)
+	}
+
+	fmt.Printf("\n=== Transport Requests ===\n")
+	fmt.Printf("Found %d modifiable requests:\n", len(transports))
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, request := range transports {
+		fmt.Printf("• %s - %s (%s", request.RequestID, request.Description, request.Owner)
+		if request.Target != "" {
+			fmt.Printf(", target %s", request.Target)
+		}
+		fmt.Printf(", %d objects)\n", len(request.Objects))
+		for _, task := range request.Tasks {
+			fmt.Printf("  └── %s - %s (%s, %s, %d objects)\n", task.RequestID, task.Description, task.Owner, task.Status, len(task.Objects))
+		}
+	}
+
+	return nil
+}
+
+// findTransport looks up a request or task among the user's modifiable requests
+func findTransport(transports []types.ADTTransport, requestID string) *types.ADTTransport {
+	for i := range transports {
+		if strings.EqualFold(transports[i].RequestID, requestID) {
+			return &transports[i]
+		}
+		if task := findTransport(transports[i].Tasks, requestID); task != nil {
+			return task
+		}
+	}
+	return nil
+}
+
+// HandleTransportObjects lists the objects of a transport request and its tasks
+func HandleTransportObjects(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectName == "" {
+		return fmt.Errorf("transport request required: %s transport objects TRKORR", "abaper")
+	}
+
+	transports, err := adtClient.GetTransports()
+	if err != nil {
+		return fmt.Errorf("failed to list transport requests: %w", err)
+	}
+
+	request := findTransport(transports, config.ObjectName)
+	if request == nil {
+		return fmt.Errorf("transport request %s not found among your modifiable requests", strings.ToUpper(config.ObjectName))
+	}
+
+	fmt.Printf("\n=== Transport %s ===\n", request.RequestID)
+	fmt.Printf("Description: %s\n", request.Description)
+	fmt.Printf("Owner: %s\n", request.Owner)
+
+	printObjects := func(objects []types.ADTObject, indent string) {
+		for _, obj := range objects {
+			fmt.Printf("%s• %s (%s)", indent, obj.Name, obj.Type)
+			if obj.Description != "" {
+				fmt.Printf(" - %s", obj.Description)
+			}
+			fmt.Println()
+		}
+	}
+
+	printObjects(request.Objects, "")
+	for _, task := range request.Tasks {
+		fmt.Printf("\nTask %s (%s):\n", task.RequestID, task.Owner)
+		printObjects(task.Objects, "  ")
+	}
+
+	return nil
+}
+
+// HandleTransportCreate creates a new workbench request
+func HandleTransportCreate(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectName == "" {
+		return fmt.Errorf("description required: %s transport create DESCRIPTION [TYPE NAME [GROUP]] --package PACKAGE", "abaper")
+	}
+	if config.Package == "" {
+		return fmt.Errorf("package required: %s transport create DESCRIPTION [TYPE NAME [GROUP]] --package PACKAGE", "abaper")
+	}
+
+	// Optionally the request is created for an object, which is recorded in it
+	objectURI := ""
+	if len(config.Args) > 0 {
+		if len(config.Args) < 2 {
+			return fmt.Errorf("object name required: %s transport create DESCRIPTION TYPE NAME [GROUP] --package PACKAGE", "abaper")
+		}
+		parent := ""
+		if len(config.Args) > 2 {
+			parent = config.Args[2]
+		}
+		obj, err := types.ObjectReference(config.Args[0], config.Args[1], parent)
+		if err != nil {
+			return err
+		}
+		objectURI = obj.URI
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🚚 Creating transport request for package %s...\n", strings.ToUpper(config.Package))
+	}
+
+	requestID, err := adtClient.CreateTransport(config.ObjectName, config.Package, objectURI)
+	if err != nil {
+		return fmt.Errorf("failed to create transport request: %w", err)
+	}
+
+	fmt.Printf("✅ Transport request %s created\n", requestID)
+	return nil
+}
+
+// HandleTransportCheck shows which transport request changes to an object need
+func HandleTransportCheck(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if len(config.Args) == 0 {
+		return fmt.Errorf("object required: %s transport check TYPE NAME [GROUP]", "abaper")
+	}
+
+	parent := ""
+	if len(config.Args) > 1 {
+		parent = config.Args[1]
+	}
+	obj, err := types.ObjectReference(config.ObjectName, config.Args[0], parent)
+	if err != nil {
+		return err
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🔍 Checking transport requirements of %s %s...\n", obj.Type, obj.Name)
+	}
+
+	check, err := adtClient.CheckTransport(obj.URI, config.Package)
+	if err != nil {
+		return fmt.Errorf("transport check failed: %w", err)
+	}
+
+	fmt.Printf("\n=== Transport Check %s %s ===\n", obj.Type, obj.Name)
+	if check.Package != "" {
+		fmt.Printf("Package: %s\n", check.Package)
+	}
+	if check.DeliveryUnit != "" {
+		fmt.Printf("Software component: %s\n", check.DeliveryUnit)
+	}
+	if !check.Recording {
+		fmt.Println("No transport request needed (local object)")
+	} else if check.LockedIn != "" {
+		fmt.Printf("Already locked in request %s - changes must use it\n", check.LockedIn)
+	} else {
+		fmt.Println("Changes must be recorded in a transport request")
+		if len(check.Transports) > 0 {
+			fmt.Println("Available requests:")
+			for _, request := range check.Transports {
+				fmt.Printf("  • %s - %s (%s)\n", request.RequestID, request.Description, request.Owner)
+			}
+		}
+	}
+	for _, msg := range check.Messages {
+		fmt.Printf("ℹ️ %s\n", msg)
+	}
+
+	return nil
+}
+
+// HandleTransportAssign records an object in a transport request or task
+func HandleTransportAssign(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectName == "" || len(config.Args) < 2 {
+		return fmt.Errorf("request and object required: %s transport assign TRKORR TYPE NAME [GROUP]", "abaper")
+	}
+
+	parent := ""
+	if len(config.Args) > 2 {
+		parent = config.Args[2]
+	}
+	obj, err := types.ObjectReference(config.Args[0], config.Args[1], parent)
+	if err != nil {
+		return err
+	}
+
+	requestID := strings.ToUpper(config.ObjectName)
+	if !quiet || normal {
+		fmt.Printf("🚚 Assigning %s %s to %s...\n", obj.Type, obj.Name, requestID)
+	}
+
+	if err := adtClient.AssignTransport(obj.URI, requestID); err != nil {
+		return fmt.Errorf("failed to assign %s %s to %s: %w", obj.Type, obj.Name, requestID, err)
+	}
+
+	fmt.Printf("✅ %s %s recorded in %s\n", obj.Type, obj.Name, requestID)
+	return nil
+}
+
+// HandleTransportRelease releases a transport request or task, optionally releasing its open tasks first
+func HandleTransportRelease(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectName == "" {
+		return fmt.Errorf("transport request required: %s transport release TRKORR", "abaper")
+	}
+
+	requestIDs := []string{}
+	if config.ReleaseTasks {
+		transports, err := adtClient.GetTransports()
+		if err != nil {
+			return fmt.Errorf("failed to list transport requests: %w", err)
+		}
+		request := findTransport(transports, config.ObjectName)
+		if request == nil {
+			return fmt.Errorf("transport request %s not found among your modifiable requests", strings.ToUpper(config.ObjectName))
+		}
+		for _, task := range request.Tasks {
+			if task.Status != "R" {
+				requestIDs = append(requestIDs, task.RequestID)
+			}
+		}
+	}
+	requestIDs = append(requestIDs, strings.ToUpper(config.ObjectName))
+
+	for _, requestID := range requestIDs {
+		if !quiet || normal {
+			fmt.Printf("🚚 Releasing %s...\n", requestID)
+		}
+
+		result, err := adtClient.ReleaseTransport(requestID)
+		if err != nil {
+			return fmt.Errorf("failed to release %s: %w", requestID, err)
+		}
+
+		for _, msg := range result.Messages {
+			icon := "ℹ️"
+			switch msg.Severity {
+			case "error":
+				icon = "❌"
+			case "warning":
+				icon = "⚠️"
+			}
+			fmt.Printf("%s %s\n", icon, msg.Text)
+		}
+
+		if !result.Released {
+			return fmt.Errorf("%s was not released: %s", requestID, result.Text)
+		}
+		fmt.Printf("✅ %s released\n", requestID)
+	}
+
+	return nil
+}
+
+// HandleConnect tests ADT connection
+func HandleConnect(config *CommandConfig, adtClient types.ADTClient, quiet bool, normal bool) error {
+	if !quiet || normal {
+		fmt.Println("🔌 Testing ADT connection...")
+	}
+
+	if adtClient == nil {
+		return fmt.Errorf("ADT client not configured")
+	}
+
+	if err := adtClient.TestConnection(); err != nil {
+		fmt.Printf("❌ Connection failed: %v\n", err)
+		fmt.Println("\n💡 Troubleshooting tips:")
+		fmt.Println("  1. Check your SAP credentials and host configuration")
+		fmt.Println("  2. Verify ADT services are activated in SICF (transaction SICF)")
+		fmt.Println("  3. Ensure your user has S_DEVELOP authorization")
+		fmt.Println("  4. Test basic connectivity: ping <host>")
+		return err
+	}
+
+	fmt.Println("✅ ADT connection successful!")
+	return nil
+}
+
+// getObjectSource retrieves source code for any supported object type
+func getObjectSource(config *CommandConfig, adtClient types.ADTClient) (*types.ADTSourceCode, error) {
+	objectType := normalizeObjectType(config.ObjectType)
+	objectName := strings.ToUpper(config.ObjectName)
+
+	switch objectType {
+	case "PROGRAM":
+		return adtClient.GetProgram(objectName)
+	case "CLASS":
+		return adtClient.GetClass(objectName)
+	case "FUNCTION":
+		if len(config.Args) == 0 {
+			return nil, fmt.Errorf("function group required for function")
+		}
+		functionGroup := strings.ToUpper(config.Args[0])
+		return adtClient.GetFunction(objectName, functionGroup)
+	case "INCLUDE":
+		return adtClient.GetInclude(objectName)
+	case "INTERFACE":
+		return adtClient.GetInterface(objectName)
+	case "STRUCTURE":
+		return adtClient.GetStructure(objectName)
+	case "TABLE":
+		return adtClient.GetTable(objectName)
+	default:
+		return nil, fmt.Errorf("unsupported object type for source retrieval: %s", objectType)
+	}
+}
+
+// CreateADTClient creates ADT client from configuration
+func CreateADTClient(config *Config) (types.ADTClient, error) {
+	if config.ADTHost == "" {
+		return nil, fmt.Errorf("ADT host not configured (use --adt-host, set SAP_HOST or select a system with --system)")
+	}
+
+	if config.ADTUsername == "" {
+		return nil, fmt.Errorf("ADT username not configured (use --adt-username, set SAP_USERNAME or add --user to the system profile)")
+	}
+
+	adtConfig := &types.ADTConfig{
+		Host:            config.ADTHost,
+		Client:          config.ADTClient,
+		Username:        config.ADTUsername,
+		Password:        config.ADTPassword,
+		Language:        config.Language,
+		AllowSelfSigned: config.AllowSelfSigned,
+		CAFile:          config.CAFile,
+		ConnectTimeout:  config.ConnectTimeout,
+		RequestTimeout:  config.RequestTimeout,
+		Debug:           false,
+	}
+
+	// Defaults when no profile sets them
+	if adtConfig.Language == "" {
+		adtConfig.Language = "EN"
+	}
+	if adtConfig.ConnectTimeout == 0 {
+		adtConfig.ConnectTimeout = 30
+	}
+	if adtConfig.RequestTimeout == 0 {
+		adtConfig.RequestTimeout = 120
+	}
+
+	// Set default client if not specified
+	if adtConfig.Client == "" {
+		adtConfig.Client = "100"
+	}
+
+	client := NewADTClient(adtConfig).(*ADTClientImpl)
+
+	// Force stateful session BEFORE authentication
 	client.SetSessionType(types.SessionStateful)
 
+	// The password is looked up once, to log on or to decrypt a saved session
+	var password string
+	client.passwordSource = func() (string, error) {
+		if password != "" {
+			return password, nil
+		}
+		resolved, err := resolvePassword(config)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stored password: %w", err)
+		}
+		password = resolved
+		if password == "" && config.System != "" {
+			return "", fmt.Errorf("ADT password of system %s not configured (run '%s login --system %s' or set a password file in the profile)", config.System, PROGRAM_NAME, config.System)
+		}
+		if password == "" {
+			return "", fmt.Errorf("ADT password not configured (run '%s login', set SAP_PASSWORD or use --adt-password)", PROGRAM_NAME)
+		}
+		return password, nil
+	}
+	client.sessionSaved = func(session *adtSession) {
+		saveSession(config, password, session)
+	}
+
+	// Continue the session of an earlier invocation; it is renewed when it has expired
+	if !config.NoSession {
+		if _, err := client.passwordSource(); err != nil {
+			return nil, fmt.Errorf("ADT authentication failed: %w", err)
+		}
+		if session := loadSession(config, password); session != nil {
+			client.restoreSession(session)
+			saveSession(config, password, client.session())
+			return client, nil
+		}
+	}
+
 	// Test authentication
 	if err := client.Authenticate(); err != nil {
 		return nil, fmt.Errorf("ADT authentication failed: %w", err)
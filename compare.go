@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bluefunda/abaper/diff"
+	"github.com/bluefunda/abaper/types"
+)
+
+// Compare outcomes per object
+const (
+	compareIdentical = "identical"
+	compareDifferent = "different"
+	compareOnlyLeft  = "only-left"
+	compareOnlyRight = "only-right"
+	compareSkipped   = "skipped"
+	compareFailed    = "failed"
+)
+
+// compareSourceMain labels the main source of an object
+const compareSourceMain = ""
+
+// comparedSource is one source of an object, e.g. a class include or a function module
+type comparedSource struct {
+	Label  string
+	Source string
+}
+
+// objectSources reads all sources of an object. Objects without source return nil.
+func objectSources(obj types.ADTObject, adtClient types.ADTClient) ([]comparedSource, error) {
+	switch obj.Type {
+	case types.ObjectTypeProgram, types.ObjectTypeInclude, types.ObjectTypeInterface,
+		types.ObjectTypeFunction, types.ObjectTypeTable, types.ObjectTypeStructure:
+		source, err := adtClient.GetSourceByURI(obj.URI + "/source/main")
+		if err != nil {
+			return nil, err
+		}
+		return []comparedSource{{Label: compareSourceMain, Source: source.Source}}, nil
+
+	case types.ObjectTypeClass:
+		includes, err := adtClient.GetClassIncludes(obj.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		var sources []comparedSource
+		for _, include := range includes {
+			label := include.Include
+			if label == types.ClassIncludeMain {
+				label = compareSourceMain
+			}
+			sources = append(sources, comparedSource{Label: label, Source: include.Source.Source})
+		}
+		return sources, nil
+
+	case types.ObjectTypeFunctionGroup:
+		main, err := adtClient.GetFunctionGroup(obj.Name)
+		if err != nil {
+			return nil, err
+		}
+		sources := []comparedSource{{Label: compareSourceMain, Source: main.Source}}
+
+		contents, err := adtClient.GetFunctionGroupContents(obj.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range contents {
+			var source *types.ADTSourceCode
+			switch member.Type {
+			case types.ObjectTypeFunction:
+				source, err = adtClient.GetFunction(member.Name, obj.Name)
+			case types.ObjectTypeFunctionInc:
+				source, err = adtClient.GetSourceByURI(member.URI + "/source/main")
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", member.Name, err)
+			}
+			sources = append(sources, comparedSource{Label: member.Name, Source: source.Source})
+		}
+		return sources, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// sourceName labels a source of an object in diff headers
+func sourceName(system string, obj types.ADTObject, label string) string {
+	if label == compareSourceMain {
+		return fmt.Sprintf("%s:%s", system, obj.Name)
+	}
+	return fmt.Sprintf("%s:%s (%s)", system, obj.Name, label)
+}
+
+// compareSources diffs the sources of an object in two systems and returns the unified diffs
+func compareSources(obj types.ADTObject, leftName, rightName string, left, right []comparedSource) string {
+	rightByLabel := make(map[string]string)
+	for _, source := range right {
+		rightByLabel[source.Label] = source.Source
+	}
+
+	var out strings.Builder
+	seen := make(map[string]bool)
+	for _, source := range left {
+		seen[source.Label] = true
+		out.WriteString(diff.Unified(
+			sourceName(leftName, obj, source.Label),
+			sourceName(rightName, obj, source.Label),
+			source.Source, rightByLabel[source.Label], diff.DefaultContext))
+	}
+	for _, source := range right {
+		if seen[source.Label] {
+			continue
+		}
+		out.WriteString(diff.Unified(
+			sourceName(leftName, obj, source.Label),
+			sourceName(rightName, obj, source.Label),
+			"", source.Source, diff.DefaultContext))
+	}
+	return out.String()
+}
+
+// HandleCompare compares an object, or all objects of a package, between two systems
+func HandleCompare(config *CommandConfig, left, right types.ADTClient, quiet bool, normal bool) error {
+	if config.ObjectType == "" || config.ObjectName == "" {
+		return fmt.Errorf("object type and name required for compare action")
+	}
+	if normalizeObjectType(config.ObjectType) == "PACKAGE" {
+		return HandleComparePackage(config, left, right, quiet, normal)
+	}
+
+	parent := ""
+	if len(config.Args) > 0 {
+		parent = config.Args[0]
+	}
+	obj, err := types.ObjectReference(config.ObjectType, config.ObjectName, parent)
+	if err != nil {
+		return err
+	}
+
+	if !quiet || normal {
+		fmt.Printf("🔀 Comparing %s %s between %s and %s...\n", obj.Type, obj.Name, config.Left, config.Right)
+	}
+
+	leftSources, leftErr := objectSources(obj, left)
+	rightSources, rightErr := objectSources(obj, right)
+	switch {
+	case isNotFound(leftErr) && isNotFound(rightErr):
+		return fmt.Errorf("%s %s exists in neither %s nor %s", obj.Type, obj.Name, config.Left, config.Right)
+	case isNotFound(leftErr) && rightErr == nil:
+		fmt.Printf("➡️  %s %s only exists in %s\n", obj.Type, obj.Name, config.Right)
+		return nil
+	case isNotFound(rightErr) && leftErr == nil:
+		fmt.Printf("⬅️  %s %s only exists in %s\n", obj.Type, obj.Name, config.Left)
+		return nil
+	case leftErr != nil:
+		return fmt.Errorf("failed to read %s %s from %s: %w", obj.Type, obj.Name, config.Left, leftErr)
+	case rightErr != nil:
+		return fmt.Errorf("failed to read %s %s from %s: %w", obj.Type, obj.Name, config.Right, rightErr)
+	case leftSources == nil && rightSources == nil:
+		return fmt.Errorf("comparing objects of type %s is not supported", obj.Type)
+	}
+
+	unified := compareSources(obj, config.Left, config.Right, leftSources, rightSources)
+	if unified == "" {
+		fmt.Printf("✅ %s %s is identical in %s and %s\n", obj.Type, obj.Name, config.Left, config.Right)
+		return nil
+	}
+
+	fmt.Print(unified)
+	return nil
+}
+
+// packageObjects lists the objects of a package, including subpackages when recursive
+func packageObjects(packageName string, recursive bool, adtClient types.ADTClient) ([]types.ADTObject, error) {
+	if !recursive {
+		pkg, err := adtClient.GetPackageContents(packageName)
+		if err != nil {
+			return nil, err
+		}
+		var objects []types.ADTObject
+		for _, obj := range pkg.Objects {
+			if obj.Type != types.ObjectTypePackage {
+				objects = append(objects, obj)
+			}
+		}
+		return objects, nil
+	}
+
+	tree, err := adtClient.GetPackageTree(packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []types.ADTObject
+	var walk func(node types.ADTNode)
+	walk = func(node types.ADTNode) {
+		for _, child := range node.Children {
+			if child.Type == types.ObjectTypePackage {
+				walk(child)
+				continue
+			}
+			objects = append(objects, types.ADTObject{Name: child.Name, Type: child.Type, Description: child.Description, URI: child.URI})
+		}
+	}
+	walk(*tree)
+	return objects, nil
+}
+
+// HandleComparePackage compares all objects of a package between two systems and prints a summary
+func HandleComparePackage(config *CommandConfig, left, right types.ADTClient, quiet bool, normal bool) error {
+	packageName := strings.ToUpper(config.ObjectName)
+
+	if !quiet || normal {
+		fmt.Printf("📦 Comparing package %s between %s and %s...\n", packageName, config.Left, config.Right)
+	}
+
+	leftObjects, err := packageObjects(packageName, config.Recursive, left)
+	if err != nil {
+		return fmt.Errorf("failed to list package %s in %s: %w", packageName, config.Left, err)
+	}
+	rightObjects, err := packageObjects(packageName, config.Recursive, right)
+	if err != nil {
+		return fmt.Errorf("failed to list package %s in %s: %w", packageName, config.Right, err)
+	}
+
+	// Union of both systems keyed by type and name
+	type entry struct {
+		obj         types.ADTObject
+		left, right bool
+	}
+	entries := make(map[string]*entry)
+	for _, obj := range leftObjects {
+		entries[obj.Type+" "+obj.Name] = &entry{obj: obj, left: true}
+	}
+	for _, obj := range rightObjects {
+		key := obj.Type + " " + obj.Name
+		if e, ok := entries[key]; ok {
+			e.right = true
+		} else {
+			entries[key] = &entry{obj: obj, right: true}
+		}
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	counts := make(map[string]int)
+	var diffs strings.Builder
+	fmt.Printf("\n=== Compare %s: %s ↔ %s ===\n", packageName, config.Left, config.Right)
+	for _, key := range keys {
+		e := entries[key]
+		result, detail := compareSkipped, ""
+
+		switch {
+		case !e.right:
+			result = compareOnlyLeft
+		case !e.left:
+			result = compareOnlyRight
+		default:
+			obj, err := types.ObjectReference(e.obj.Type, e.obj.Name, "")
+			if err != nil {
+				detail = "not supported"
+				break
+			}
+			leftSources, leftErr := objectSources(obj, left)
+			rightSources, rightErr := objectSources(obj, right)
+			switch {
+			case leftErr != nil:
+				result, detail = compareFailed, fmt.Sprintf("%s: %v", config.Left, leftErr)
+			case rightErr != nil:
+				result, detail = compareFailed, fmt.Sprintf("%s: %v", config.Right, rightErr)
+			case leftSources == nil && rightSources == nil:
+				detail = "no source"
+			default:
+				unified := compareSources(obj, config.Left, config.Right, leftSources, rightSources)
+				if unified == "" {
+					result = compareIdentical
+				} else {
+					result = compareDifferent
+					diffs.WriteString(unified)
+				}
+			}
+		}
+		counts[result]++
+
+		// Identical and skipped objects are only listed in normal mode
+		icon := ""
+		switch result {
+		case compareDifferent:
+			icon = "≠ "
+		case compareOnlyLeft:
+			icon, detail = "⬅️ ", "only in "+config.Left
+		case compareOnlyRight:
+			icon, detail = "➡️ ", "only in "+config.Right
+		case compareFailed:
+			icon = "❌"
+		case compareIdentical:
+			if !quiet || normal {
+				icon = "= "
+			}
+		case compareSkipped:
+			if !quiet || normal {
+				icon = "➖"
+			}
+		}
+		if icon == "" {
+			continue
+		}
+		fmt.Printf("%s %s %s: %s", icon, e.obj.Type, e.obj.Name, result)
+		if detail != "" {
+			fmt.Printf(" (%s)", detail)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\nIdentical: %d, Different: %d, Only in %s: %d, Only in %s: %d, Skipped: %d, Failed: %d\n",
+		counts[compareIdentical], counts[compareDifferent], config.Left, counts[compareOnlyLeft],
+		config.Right, counts[compareOnlyRight], counts[compareSkipped], counts[compareFailed])
+
+	if config.ShowDiff && diffs.Len() > 0 {
+		fmt.Println()
+		fmt.Print(diffs.String())
+	}
+
+	if counts[compareFailed] > 0 {
+		return fmt.Errorf("%d object(s) could not be compared", counts[compareFailed])
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Key derivation settings of the keyring file
+const (
+	keyringVersion    = 1
+	keyringIterations = 600000
+	keyringSaltSize   = 16
+)
+
+// keyringFile is the on-disk format: the password map encrypted with AES-256-GCM under
+// a key derived from the passphrase with PBKDF2-SHA256
+type keyringFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// fileStore keeps passwords in an encrypted file for systems without a keyring service
+type fileStore struct {
+	path       string
+	passphrase func() (string, error)
+
+	// Set once the file is unlocked or created
+	salt []byte
+	key  []byte
+}
+
+func newFileStore(options Options) (*fileStore, error) {
+	if options.Dir == "" {
+		return nil, fmt.Errorf("keyring directory not configured")
+	}
+	passphrase := options.Passphrase
+	if passphrase == nil {
+		passphrase = func() (string, error) {
+			return "", fmt.Errorf("keyring passphrase required (set ABAPER_KEYRING_PASSPHRASE)")
+		}
+	}
+	return &fileStore{path: filepath.Join(options.Dir, "keyring"), passphrase: passphrase}, nil
+}
+
+func (f *fileStore) Name() string { return BackendFile }
+
+// load decrypts the keyring file. A missing file is an empty keyring and needs no passphrase.
+func (f *fileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring file %s: %w", f.path, err)
+	}
+	if file.Version != keyringVersion {
+		return nil, fmt.Errorf("unsupported keyring file version %d", file.Version)
+	}
+
+	if f.key == nil {
+		if err := f.unlock(file.Salt, file.Iterations); err != nil {
+			return nil, err
+		}
+	}
+	aead, err := f.cipher()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keyring file %s: wrong passphrase?", f.path)
+	}
+
+	passwords := map[string]string{}
+	if err := json.Unmarshal(plain, &passwords); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring contents: %w", err)
+	}
+	return passwords, nil
+}
+
+// unlock derives the encryption key from the passphrase
+func (f *fileStore) unlock(salt []byte, iterations int) error {
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("keyring passphrase must not be empty")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return fmt.Errorf("failed to derive keyring key: %w", err)
+	}
+	f.salt, f.key = salt, key
+	return nil
+}
+
+func (f *fileStore) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(f.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// save encrypts the passwords with a fresh nonce and replaces the keyring file
+func (f *fileStore) save(passwords map[string]string) error {
+	if f.key == nil {
+		salt := make([]byte, keyringSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		if err := f.unlock(salt, keyringIterations); err != nil {
+			return err
+		}
+	}
+	aead, err := f.cipher()
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(passwords)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(keyringFile{
+		Version:    keyringVersion,
+		Iterations: keyringIterations,
+		Salt:       f.salt,
+		Nonce:      nonce,
+		Data:       aead.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("failed to create keyring directory: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keyring file: %w", err)
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *fileStore) Get(key Key) (string, error) {
+	passwords, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	password, ok := passwords[key.String()]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return password, nil
+}
+
+func (f *fileStore) Set(key Key, password string) error {
+	passwords, err := f.load()
+	if err != nil {
+		return err
+	}
+	passwords[key.String()] = password
+	return f.save(passwords)
+}
+
+func (f *fileStore) Delete(key Key) error {
+	passwords, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := passwords[key.String()]; !ok {
+		return ErrNotFound
+	}
+	delete(passwords, key.String())
+	return f.save(passwords)
+}
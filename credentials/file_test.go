@@ -0,0 +1,121 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openFileStore(t *testing.T, dir, passphrase string) Store {
+	t.Helper()
+	store, err := Open(BackendFile, Options{
+		Dir:        dir,
+		Passphrase: func() (string, error) { return passphrase, nil },
+	})
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	return store
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	dev := NewKey("sapdev.example.com:44300", "100", "developer")
+	qas := NewKey("sapqas.example.com:44300", "200", "developer")
+
+	store := openFileStore(t, dir, "correct horse")
+	if _, err := store.Get(dev); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() on empty keyring = %v, want ErrNotFound", err)
+	}
+	if err := store.Set(dev, "dev-secret"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := store.Set(qas, "qas-secret"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "keyring"))
+	if err != nil {
+		t.Fatalf("keyring file not written: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("keyring file mode = %o, want 600", mode)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "keyring"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "dev-secret") {
+		t.Errorf("keyring file contains the password in plain text")
+	}
+
+	// A new store has to derive the key from the passphrase again
+	reopened := openFileStore(t, dir, "correct horse")
+	for key, want := range map[Key]string{dev: "dev-secret", qas: "qas-secret"} {
+		got, err := reopened.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%s) failed: %v", key, err)
+		}
+		if got != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	if err := reopened.Delete(dev); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := reopened.Get(dev); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() = %v, want ErrNotFound", err)
+	}
+	if err := reopened.Delete(dev); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete() = %v, want ErrNotFound", err)
+	}
+	if got, err := reopened.Get(qas); err != nil || got != "qas-secret" {
+		t.Errorf("Get(%s) after deleting another key = %q, %v", qas, got, err)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	key := NewKey("sapdev.example.com:44300", "100", "developer")
+
+	if err := openFileStore(t, dir, "correct horse").Set(key, "dev-secret"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	store := openFileStore(t, dir, "wrong horse")
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"get", func() error { _, err := store.Get(key); return err }},
+		{"set", func() error { return store.Set(key, "other") }},
+		{"delete", func() error { return store.Delete(key) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil || errors.Is(err, ErrNotFound) {
+				t.Fatalf("error = %v, want a decryption error", err)
+			}
+			if !strings.Contains(err.Error(), "wrong passphrase") {
+				t.Errorf("error = %v, want it to mention the passphrase", err)
+			}
+		})
+	}
+
+	// Nothing was overwritten with the wrong passphrase
+	got, err := openFileStore(t, dir, "correct horse").Get(key)
+	if err != nil || got != "dev-secret" {
+		t.Errorf("Get() with the right passphrase = %q, %v", got, err)
+	}
+}
+
+func TestFileStoreEmptyPassphrase(t *testing.T) {
+	store := openFileStore(t, t.TempDir(), "")
+	if err := store.Set(NewKey("sapdev.example.com", "100", "developer"), "secret"); err == nil {
+		t.Fatal("Set() with an empty passphrase succeeded")
+	}
+}
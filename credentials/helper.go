@@ -0,0 +1,61 @@
+package credentials
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// helperStore asks an external credential helper using git's protocol: the helper is
+// run with get, store or erase and exchanges key=value lines on stdin and stdout.
+// Existing git helpers work too, e.g. "git credential-libsecret".
+type helperStore struct {
+	command string
+}
+
+func (h *helperStore) Name() string { return BackendHelper }
+
+// request renders the attributes describing a key
+func (h *helperStore) request(key Key, password string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "protocol=https\nhost=%s\npath=sap/bc/adt?sap-client=%s\nusername=%s\n", key.Host, key.Client, key.User)
+	if password != "" {
+		fmt.Fprintf(&b, "password=%s\n", password)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// run runs the helper with an action through the shell, like git does
+func (h *helperStore) run(action, input string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return runCommand(input, "cmd", "/C", h.command+" "+action)
+	}
+	return runCommand(input, "sh", "-c", h.command+" "+action)
+}
+
+func (h *helperStore) Get(key Key) (string, error) {
+	out, err := h.run("get", h.request(key, ""))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if name, value, ok := strings.Cut(strings.TrimSuffix(line, "\r"), "="); ok && name == "password" && value != "" {
+			return value, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+func (h *helperStore) Set(key Key, password string) error {
+	if strings.ContainsAny(password, "\n\x00") {
+		return fmt.Errorf("passwords with line breaks cannot be passed to a credential helper")
+	}
+	_, err := h.run("store", h.request(key, password))
+	return err
+}
+
+func (h *helperStore) Delete(key Key) error {
+	_, err := h.run("erase", h.request(key, ""))
+	return err
+}
@@ -0,0 +1,43 @@
+package credentials
+
+import (
+	"fmt"
+	"strings"
+)
+
+// passStore keeps passwords in the standard unix password manager (pass), one entry
+// per key below abaper/
+type passStore struct{}
+
+func (p *passStore) Name() string { return BackendPass }
+
+// entry returns the pass entry of a key, e.g. abaper/sapdev:44300/100/DEVELOPER
+func (p *passStore) entry(key Key) string {
+	return fmt.Sprintf("abaper/%s/%s/%s", key.Host, key.Client, key.User)
+}
+
+func (p *passStore) Get(key Key) (string, error) {
+	out, err := runCommand("", "pass", "show", p.entry(key))
+	if err != nil {
+		if strings.Contains(err.Error(), "is not in the password store") {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	// Like pass -c, only the first line is the password
+	password, _, _ := strings.Cut(out, "\n")
+	if password == "" {
+		return "", ErrNotFound
+	}
+	return password, nil
+}
+
+func (p *passStore) Set(key Key, password string) error {
+	_, err := runCommand(password+"\n", "pass", "insert", "--multiline", "--force", p.entry(key))
+	return err
+}
+
+func (p *passStore) Delete(key Key) error {
+	_, err := runCommand("", "pass", "rm", "--force", p.entry(key))
+	return err
+}
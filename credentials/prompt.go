@@ -0,0 +1,54 @@
+package credentials
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// IsTerminal reports whether stdin is an interactive terminal. Character devices like
+// /dev/null are told apart by asking stty for the terminal settings.
+func IsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	return runtime.GOOS == "windows" || stty("-g") == nil
+}
+
+// ReadSecret prompts on stderr and reads a line from the terminal with echo turned off
+func ReadSecret(prompt string) (string, error) {
+	if !IsTerminal() {
+		return "", fmt.Errorf("cannot prompt for %s: stdin is not a terminal", strings.TrimSuffix(strings.ToLower(prompt), ": "))
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	if runtime.GOOS != "windows" {
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	return ReadLine(os.Stdin)
+}
+
+// ReadLine reads one line, e.g. a password piped to --password-stdin
+func ReadLine(file *os.File) (string, error) {
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// stty changes the terminal settings of stdin
+func stty(setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,40 @@
+package credentials
+
+import "strings"
+
+// secretService keeps passwords in the Secret Service (GNOME Keyring, KWallet) via secret-tool
+type secretService struct{}
+
+func (s *secretService) Name() string { return BackendSecretService }
+
+// attributes identify a password in the Secret Service
+func (s *secretService) attributes(key Key) []string {
+	return []string{"service", "abaper", "host", key.Host, "client", key.Client, "user", key.User}
+}
+
+func (s *secretService) Get(key Key) (string, error) {
+	out, err := runCommand("", "secret-tool", append([]string{"lookup"}, s.attributes(key)...)...)
+	if err != nil {
+		// secret-tool exits with 1 when nothing matches
+		if exitCode(err) == 1 {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	if out == "" {
+		return "", ErrNotFound
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (s *secretService) Set(key Key, password string) error {
+	// The password is passed on stdin so it never shows up in the process list
+	args := append([]string{"store", "--label=abaper " + key.String()}, s.attributes(key)...)
+	_, err := runCommand(password, "secret-tool", args...)
+	return err
+}
+
+func (s *secretService) Delete(key Key) error {
+	_, err := runCommand("", "secret-tool", append([]string{"clear"}, s.attributes(key)...)...)
+	return err
+}
@@ -0,0 +1,138 @@
+// Package credentials stores SAP passwords outside of flags, environment and shell history
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Backend names
+const (
+	BackendAuto          = "auto"
+	BackendSecretService = "secret-service"
+	BackendFile          = "file"
+	BackendPass          = "pass"
+	BackendHelper        = "helper"
+)
+
+// Backends lists the selectable backends
+var Backends = []string{BackendAuto, BackendSecretService, BackendFile, BackendPass, BackendHelper}
+
+// ErrNotFound is returned when a store holds no password for a key
+var ErrNotFound = errors.New("credentials not found")
+
+// Key identifies the password of a user on a system and client
+type Key struct {
+	Host   string
+	Client string
+	User   string
+}
+
+// NewKey normalizes host, client and user; the scheme and trailing slashes of the host
+// are dropped and the user is upper case like SAP user names
+func NewKey(host, client, user string) Key {
+	host = strings.TrimSpace(strings.ToLower(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(strings.TrimSuffix(host, "/"), "/sap/bc/adt")
+	if client == "" {
+		client = "100"
+	}
+	return Key{Host: host, Client: client, User: strings.ToUpper(strings.TrimSpace(user))}
+}
+
+// String renders the key as USER@host/client
+func (k Key) String() string {
+	return fmt.Sprintf("%s@%s/%s", k.User, k.Host, k.Client)
+}
+
+// Store reads and writes passwords
+type Store interface {
+	Name() string
+	Get(key Key) (string, error)
+	Set(key Key, password string) error
+	Delete(key Key) error
+}
+
+// Options configures the backends
+type Options struct {
+	Dir        string                 // directory of the encrypted keyring file
+	Helper     string                 // credential helper command
+	Passphrase func() (string, error) // passphrase of the keyring file
+}
+
+// Open returns the store of a backend. Auto selects the Secret Service when secret-tool
+// is installed and the encrypted keyring file otherwise.
+func Open(backend string, options Options) (Store, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendAuto:
+		if _, err := exec.LookPath("secret-tool"); err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+			return &secretService{}, nil
+		}
+		return newFileStore(options)
+	case BackendSecretService, "keyring":
+		return &secretService{}, nil
+	case BackendFile:
+		return newFileStore(options)
+	case BackendPass:
+		return &passStore{}, nil
+	case BackendHelper:
+		if strings.TrimSpace(options.Helper) == "" {
+			return nil, fmt.Errorf("credential helper command not configured")
+		}
+		return &helperStore{command: options.Helper}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential backend: %s (use %s)", backend, strings.Join(Backends, ", "))
+	}
+}
+
+// ReadPasswordFile reads a pass-style file: the first line is the password, anything
+// after it is ignored. Files readable by group or others are refused.
+func ReadPasswordFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("password file %s is accessible by others, run: chmod 600 %s", path, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	password, _, _ := strings.Cut(string(data), "\n")
+	password = strings.TrimSuffix(password, "\r")
+	if password == "" {
+		return "", fmt.Errorf("password file %s is empty", path)
+	}
+	return password, nil
+}
+
+// runCommand runs a backend command with input on stdin and returns its output
+func runCommand(input string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %s: %w", name, msg, err)
+		}
+		return "", fmt.Errorf("%s failed: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// exitCode returns the exit code of a failed command, or -1 if it did not run
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,244 @@
+// Package diff produces unified diffs of ABAP sources
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around changes
+const DefaultContext = 3
+
+// edit kinds
+const (
+	equal = iota
+	del
+	ins
+)
+
+// edit is one line of the edit script; a and b are line indexes in the old and new text
+type edit struct {
+	kind int
+	a, b int
+}
+
+// Lines splits a source into lines, ignoring line endings and a final newline
+func Lines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// Equal reports whether two sources have the same lines
+func Equal(a, b string) bool {
+	linesA, linesB := Lines(a), Lines(b)
+	if len(linesA) != len(linesB) {
+		return false
+	}
+	for i := range linesA {
+		if linesA[i] != linesB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Unified returns the unified diff of two sources with context lines around each change.
+// It returns an empty string if the sources are equal.
+func Unified(fromName, toName, from, to string, context int) string {
+	a, b := Lines(from), Lines(to)
+	edits := script(a, b)
+
+	var out strings.Builder
+	for _, hunk := range hunks(edits, context) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		first := edits[hunk[0]]
+		countA, countB := 0, 0
+		for _, e := range edits[hunk[0]:hunk[1]] {
+			if e.kind != ins {
+				countA++
+			}
+			if e.kind != del {
+				countB++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(first.a, countA), hunkRange(first.b, countB))
+
+		for _, e := range edits[hunk[0]:hunk[1]] {
+			switch e.kind {
+			case equal:
+				out.WriteString(" " + a[e.a] + "\n")
+			case del:
+				out.WriteString("-" + a[e.a] + "\n")
+			case ins:
+				out.WriteString("+" + b[e.b] + "\n")
+			}
+		}
+	}
+	return out.String()
+}
+
+// hunkRange formats the start,count part of a hunk header
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// hunks groups the edit script into [start, end) ranges around changes, merging
+// changes that are less than two contexts apart
+func hunks(edits []edit, context int) [][2]int {
+	var result [][2]int
+	for i := 0; i < len(edits); i++ {
+		if edits[i].kind == equal {
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(edits) {
+			// Extend over the change and following equal lines while another change is close
+			for end < len(edits) && edits[end].kind != equal {
+				end++
+			}
+			next := end
+			for next < len(edits) && edits[next].kind == equal {
+				next++
+			}
+			if next < len(edits) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			break
+		}
+		stop := end + context
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+
+		result = append(result, [2]int{start, stop})
+		i = stop - 1
+	}
+	return result
+}
+
+// script computes the shortest edit script between a and b (Myers' algorithm)
+func script(a, b []string) []edit {
+	// Common prefix and suffix need no search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{kind: equal, a: i, b: i})
+	}
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		e.a += prefix
+		e.b += prefix
+		edits = append(edits, e)
+	}
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, edit{kind: equal, a: len(a) - suffix + i, b: len(b) - suffix + i})
+	}
+	return edits
+}
+
+// myers returns the edit script of a and b. Only the diagonals reached in each round
+// are kept for backtracking, so memory grows with the square of the edit distance.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		// Keep v[-d..d] as it was at the start of this round
+		trace = append(trace, append([]int(nil), v[max-d:max+d+1]...))
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	var reversed []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		round := trace[d]
+		at := func(k int) int { return round[k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, edit{kind: equal, a: x, b: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, edit{kind: ins, a: x, b: prevY})
+			} else {
+				reversed = append(reversed, edit{kind: del, a: prevX, b: y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
@@ -0,0 +1,84 @@
+package diff
+
+import "testing"
+
+const base = "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "equal",
+			from: base,
+			to:   base,
+			want: "",
+		},
+		{
+			name: "line endings only",
+			from: "a\r\nb\r\n",
+			to:   "a\nb",
+			want: "",
+		},
+		{
+			name: "insert at start",
+			from: base,
+			to:   "new\n" + base,
+			want: "--- old\n+++ new\n@@ -1,3 +1,4 @@\n+new\n a\n b\n c\n",
+		},
+		{
+			name: "delete at start",
+			from: base,
+			to:   "b\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,3 @@\n-a\n b\n c\n d\n",
+		},
+		{
+			name: "insert at end",
+			from: base,
+			to:   base + "new\n",
+			want: "--- old\n+++ new\n@@ -10,3 +10,4 @@\n j\n k\n l\n+new\n",
+		},
+		{
+			name: "delete at end",
+			from: base,
+			to:   "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n",
+			want: "--- old\n+++ new\n@@ -9,4 +9,3 @@\n i\n j\n k\n-l\n",
+		},
+		{
+			name: "close changes merge into one hunk",
+			from: base,
+			to:   "a\nB\nc\nd\ne\nf\nG\nh\ni\nj\nk\nl\n",
+			want: "--- old\n+++ new\n@@ -1,10 +1,10 @@\n a\n-b\n+B\n c\n d\n e\n f\n-g\n+G\n h\n i\n j\n",
+		},
+		{
+			name: "distant changes get separate hunks",
+			from: base,
+			to:   "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nL\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -9,4 +9,4 @@\n i\n j\n k\n-l\n+L\n",
+		},
+		{
+			name: "from empty",
+			from: "",
+			to:   "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty",
+			from: "a\n",
+			to:   "",
+			want: "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("old", "new", tt.from, tt.to, DefaultContext)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bluefunda/abaper/credentials"
+	"go.uber.org/zap"
+)
+
+// credentialStore opens the password store configured for a system. The encrypted
+// keyring file lives next to the config file.
+func credentialStore(config *Config) (credentials.Store, error) {
+	path, err := configPath(config)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.Open(config.Credentials, credentials.Options{
+		Dir:        filepath.Dir(path),
+		Helper:     config.CredentialHelper,
+		Passphrase: keyringPassphrase,
+	})
+}
+
+// keyringPassphrase reads the passphrase of the keyring file from ABAPER_KEYRING_PASSPHRASE
+// or the terminal
+func keyringPassphrase() (string, error) {
+	if passphrase := os.Getenv("ABAPER_KEYRING_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	return credentials.ReadSecret("Keyring passphrase: ")
+}
+
+// credentialKey identifies the password of the configured user on the configured system
+func credentialKey(config *Config) credentials.Key {
+	return credentials.NewKey(config.ADTHost, config.ADTClient, config.ADTUsername)
+}
+
+// resolvePassword looks up the password of a system when none was given as flag or
+// environment variable: from the profile's password file, else from the credential store.
+// An empty password means none is stored.
+func resolvePassword(config *Config) (string, error) {
+	if config.ADTPassword != "" {
+		return config.ADTPassword, nil
+	}
+	if config.PasswordFile != "" {
+		return credentials.ReadPasswordFile(config.PasswordFile)
+	}
+
+	store, err := credentialStore(config)
+	if err != nil {
+		return "", err
+	}
+	password, err := store.Get(credentialKey(config))
+	if errors.Is(err, credentials.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", store.Name(), err)
+	}
+
+	logger.Debug("Using stored password",
+		zap.String("backend", store.Name()),
+		zap.String("key", credentialKey(config).String()))
+	return password, nil
+}
+
+// passwordFingerprint identifies a password in cache keys without keeping it in plain text
+func passwordFingerprint(password string) string {
+	if password == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// HandleLogin verifies a password against the system and stores it in the credential store
+func HandleLogin(config *Config, passwordStdin bool, verify bool, quiet bool, normal bool) error {
+	if config.ADTHost == "" {
+		return fmt.Errorf("ADT host not configured (select a system with --system, use --adt-host or set SAP_HOST)")
+	}
+
+	if config.ADTUsername == "" {
+		if !credentials.IsTerminal() {
+			return fmt.Errorf("ADT username not configured (use --adt-username or add --user to the system profile)")
+		}
+		fmt.Fprint(os.Stderr, "Username: ")
+		username, err := credentials.ReadLine(os.Stdin)
+		if err != nil {
+			return err
+		}
+		config.ADTUsername = strings.TrimSpace(username)
+		if config.ADTUsername == "" {
+			return fmt.Errorf("username required")
+		}
+	}
+	key := credentialKey(config)
+
+	var password string
+	var err error
+	if passwordStdin {
+		password, err = credentials.ReadLine(os.Stdin)
+	} else {
+		password, err = credentials.ReadSecret(fmt.Sprintf("Password for %s: ", key))
+	}
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("password must not be empty")
+	}
+
+	// Log on afresh instead of continuing a session of the previous password
+	if err := removeSession(config); err != nil {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+
+	if verify {
+		if !quiet || normal {
+			fmt.Printf("🔐 Logging on to %s as %s...\n", config.ADTHost, key.User)
+		}
+		verifyConfig := *config
+		verifyConfig.ADTPassword = password
+		if _, err := CreateADTClient(&verifyConfig); err != nil {
+			return fmt.Errorf("login failed, password not stored: %w", err)
+		}
+	}
+
+	store, err := credentialStore(config)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(key, password); err != nil {
+		return fmt.Errorf("failed to store password in %s: %w", store.Name(), err)
+	}
+
+	fmt.Printf("✅ Password for %s stored in %s\n", key, store.Name())
+	return nil
+}
+
+// HandleLogout removes the stored password of the configured user
+func HandleLogout(config *Config, quiet bool, normal bool) error {
+	if config.ADTHost == "" || config.ADTUsername == "" {
+		return fmt.Errorf("host and username required (select a system with --system or use --adt-host and --adt-username)")
+	}
+	key := credentialKey(config)
+
+	if err := removeSession(config); err != nil {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+
+	store, err := credentialStore(config)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(key); err != nil {
+		if errors.Is(err, credentials.ErrNotFound) {
+			fmt.Printf("No password stored for %s in %s\n", key, store.Name())
+			return nil
+		}
+		return fmt.Errorf("failed to remove password from %s: %w", store.Name(), err)
+	}
+
+	fmt.Printf("🗑️  Password for %s removed from %s\n", key, store.Name())
+	return nil
+}
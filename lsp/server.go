@@ -0,0 +1,672 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// Object is the repository object behind a workspace file
+type Object struct {
+	Object    types.ADTObject // object that is activated after saving
+	SourceURI string          // source the file is read from and written to
+}
+
+// Resolver maps a file path to its repository object. It returns nil for files that are not ABAP sources.
+type Resolver func(path string) (*Object, error)
+
+// Options controls how the server writes documents back to the system
+type Options struct {
+	Transport string // transport request for saved changes
+	Activate  bool   // activate objects after saving
+	CacheDir  string // where sources of definitions outside the workspace are stored
+	Version   string
+}
+
+// document is an open editor buffer
+type document struct {
+	uri    string
+	path   string
+	text   string
+	object *Object
+	base   *types.ADTSourceCode // system version the buffer is based on, read on open and updated by saves
+}
+
+// Server is a Language Server Protocol server backed by an ADT client
+type Server struct {
+	adtClient types.ADTClient
+	logger    *zap.Logger
+	resolve   Resolver
+	options   Options
+
+	conn      *conn
+	rootPath  string
+	documents map[string]*document
+	index     map[string]string // lower-case source URI -> workspace file, built on first use
+	utf8      bool              // positions count bytes instead of UTF-16 code units
+	shutdown  bool
+}
+
+// NewServer creates a language server
+func NewServer(adtClient types.ADTClient, logger *zap.Logger, resolve Resolver, options Options) *Server {
+	return &Server{
+		adtClient: adtClient,
+		logger:    logger.With(zap.String("component", "lsp")),
+		resolve:   resolve,
+		options:   options,
+		documents: make(map[string]*document),
+	}
+}
+
+// Run serves LSP messages until the client sends exit or closes the input
+func (s *Server) Run(in io.Reader, out io.Writer) error {
+	s.conn = newConn(in, out)
+	s.logger.Info("Language server started")
+
+	for {
+		body, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.reply(json.RawMessage("null"), nil, &responseError{Code: codeParseError, Message: err.Error()})
+			continue
+		}
+
+		if msg.Method == "exit" {
+			s.logger.Info("Language server stopped", zap.Bool("shutdown", s.shutdown))
+			if !s.shutdown {
+				return fmt.Errorf("client exited without shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(&msg)
+		if len(msg.ID) == 0 {
+			if rpcErr != nil {
+				s.logger.Warn("Notification failed", zap.String("method", msg.Method), zap.String("error", rpcErr.Message))
+			}
+			continue
+		}
+		s.reply(msg.ID, result, rpcErr)
+	}
+}
+
+// handle dispatches a request or notification
+func (s *Server) handle(msg *message) (interface{}, *responseError) {
+	s.logger.Debug("Handling message", zap.String("method", msg.Method))
+
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params), nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		s.didOpen(params)
+		return nil, nil
+
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		if doc := s.documents[params.TextDocument.URI]; doc != nil && len(params.ContentChanges) > 0 {
+			doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+		return nil, nil
+
+	case "textDocument/didSave":
+		var params didSaveParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		s.didSave(params)
+		return nil, nil
+
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+		s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+			"uri":         params.TextDocument.URI,
+			"diagnostics": []Diagnostic{},
+		})
+		return nil, nil
+
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(params)
+
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params)
+
+	case "textDocument/formatting":
+		var params formattingParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.formatting(params)
+
+	default:
+		// Optional protocol notifications like $/cancelRequest can be ignored
+		if strings.HasPrefix(msg.Method, "$/") {
+			return nil, nil
+		}
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+	}
+}
+
+// initialize records the workspace root and announces the server capabilities
+func (s *Server) initialize(params initializeParams) interface{} {
+	switch {
+	case params.RootURI != "":
+		s.rootPath = uriToPath(params.RootURI)
+	case len(params.WorkspaceFolders) > 0:
+		s.rootPath = uriToPath(params.WorkspaceFolders[0].URI)
+	default:
+		s.rootPath = params.RootPath
+	}
+
+	// Positions are UTF-16 based unless the client can count bytes like the server does
+	encoding := positionEncodingUTF16
+	for _, offered := range params.Capabilities.General.PositionEncodings {
+		if offered == positionEncodingUTF8 {
+			encoding = positionEncodingUTF8
+			s.utf8 = true
+			break
+		}
+	}
+
+	s.logger.Info("Language server initialized", zap.String("root", s.rootPath), zap.String("position_encoding", encoding))
+
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"positionEncoding": encoding,
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    textDocumentSyncFull,
+				"save":      map[string]bool{"includeText": true},
+			},
+			"completionProvider":         map[string]interface{}{"triggerCharacters": []string{"-", ">", "~"}},
+			"definitionProvider":         true,
+			"documentFormattingProvider": true,
+		},
+		"serverInfo": map[string]string{
+			"name":    "abaper",
+			"version": s.options.Version,
+		},
+	}
+}
+
+// didOpen maps the document to its object, compares it with the system version and reports diagnostics
+func (s *Server) didOpen(params didOpenParams) {
+	doc := &document{
+		uri:  params.TextDocument.URI,
+		path: uriToPath(params.TextDocument.URI),
+		text: params.TextDocument.Text,
+	}
+	s.documents[doc.uri] = doc
+
+	if doc.path != "" {
+		object, err := s.resolve(doc.path)
+		if err != nil {
+			s.showMessage(messageTypeWarning, fmt.Sprintf("%s: %v", filepath.Base(doc.path), err))
+		}
+		doc.object = object
+	}
+	if doc.object == nil {
+		return
+	}
+
+	current, err := s.adtClient.GetSourceByURI(doc.object.SourceURI)
+	if err != nil {
+		s.showMessage(messageTypeWarning, fmt.Sprintf("%s %s: %v", doc.object.Object.Type, doc.object.Object.Name, err))
+		return
+	}
+	doc.base = current
+	if !sameSource(current.Source, doc.text) {
+		s.showMessage(messageTypeInfo, fmt.Sprintf("%s differs from the version on the system, saving will overwrite it", filepath.Base(doc.path)))
+	}
+
+	s.publishDiagnostics(doc)
+}
+
+// didSave writes the document back to the system and reports diagnostics
+func (s *Server) didSave(params didSaveParams) {
+	doc := s.documents[params.TextDocument.URI]
+	if doc == nil {
+		return
+	}
+	if params.Text != nil {
+		doc.text = *params.Text
+	}
+	if doc.object == nil {
+		return
+	}
+
+	if err := s.save(doc); err != nil {
+		s.showMessage(messageTypeError, fmt.Sprintf("%s: %v", filepath.Base(doc.path), err))
+	}
+	s.publishDiagnostics(doc)
+}
+
+// save updates the object source if it changed and optionally activates it. The update is
+// conditional on the version read when the document was opened or last saved, so changes
+// made on the system in the meantime are not overwritten.
+func (s *Server) save(doc *document) error {
+	if doc.base == nil {
+		return fmt.Errorf("the system version was not read when the file was opened, reopen it to save")
+	}
+	if sameSource(doc.base.Source, doc.text) {
+		return nil
+	}
+
+	update := *doc.base
+	update.Source = doc.text
+	saved, err := s.adtClient.UpdateSource(&update, s.options.Transport)
+	if err != nil {
+		return err
+	}
+	doc.base = saved
+	s.logger.Info("Document saved to system", zap.String("uri", doc.object.SourceURI))
+
+	if !s.options.Activate {
+		return nil
+	}
+
+	result, err := s.adtClient.Activate([]types.ADTObject{doc.object.Object})
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		for _, msg := range result.Messages {
+			if msg.Severity == "error" {
+				return fmt.Errorf("activation failed: %s", msg.Text)
+			}
+		}
+		return fmt.Errorf("activation failed")
+	}
+	return nil
+}
+
+// publishDiagnostics runs the syntax check on the document and reports its findings
+func (s *Server) publishDiagnostics(doc *document) {
+	result, err := s.adtClient.SyntaxCheck(doc.object.SourceURI, doc.text)
+	if err != nil {
+		s.logger.Warn("Syntax check failed", zap.String("uri", doc.object.SourceURI), zap.Error(err))
+		return
+	}
+
+	lines := strings.Split(doc.text, "\n")
+	diagnostics := []Diagnostic{}
+	for _, msg := range result.Messages {
+		// Findings in other includes of the object belong to other documents
+		if msg.URI != "" && !strings.EqualFold(msg.URI, doc.object.SourceURI) {
+			continue
+		}
+
+		severity := severityInformation
+		switch msg.Severity {
+		case "error":
+			severity = severityError
+		case "warning":
+			severity = severityWarning
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    s.tokenRange(lines, msg.Line-1, msg.Column),
+			Severity: severity,
+			Code:     msg.Code,
+			Source:   "abaper",
+			Message:  msg.Text,
+		})
+	}
+
+	s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+		"uri":         doc.uri,
+		"diagnostics": diagnostics,
+	})
+}
+
+// completion returns the code completion proposals of the system
+func (s *Server) completion(params textDocumentPositionParams) (interface{}, *responseError) {
+	doc, rpcErr := s.objectDocument(params.TextDocument.URI)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	position := params.Position
+	line := lineAt(doc.text, position.Line)
+	column := s.column(line, position.Character)
+	proposals, err := s.adtClient.GetCompletions(doc.object.SourceURI, doc.text, position.Line+1, column)
+	if err != nil {
+		return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+
+	items := []CompletionItem{}
+	for _, proposal := range proposals {
+		start := column - proposal.PrefixLength
+		if start < 0 {
+			start = 0
+		}
+		items = append(items, CompletionItem{
+			Label: proposal.Identifier,
+			TextEdit: &TextEdit{
+				Range:   Range{Start: Position{Line: position.Line, Character: s.character(line, start)}, End: position},
+				NewText: proposal.Identifier,
+			},
+		})
+	}
+	return items, nil
+}
+
+// definition resolves the symbol at the position to a location in the workspace or the source cache
+func (s *Server) definition(params textDocumentPositionParams) (interface{}, *responseError) {
+	doc, rpcErr := s.objectDocument(params.TextDocument.URI)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	position := params.Position
+	column := s.column(lineAt(doc.text, position.Line), position.Character)
+	definition, err := s.adtClient.FindDefinition(doc.object.SourceURI, doc.text, position.Line+1, column)
+	if err != nil {
+		return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+
+	uri, err := s.locate(definition.URI)
+	if err != nil {
+		return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+
+	start := Position{Line: definition.Line - 1}
+	if start.Line < 0 {
+		start.Line = 0
+	}
+	start.Character = s.character(s.documentLine(uri, start.Line), definition.Column)
+	return Location{URI: uri, Range: Range{Start: start, End: start}}, nil
+}
+
+// formatting replaces the document with the pretty printer output
+func (s *Server) formatting(params formattingParams) (interface{}, *responseError) {
+	doc := s.documents[params.TextDocument.URI]
+	if doc == nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: "document not open: " + params.TextDocument.URI}
+	}
+
+	formatted, err := s.adtClient.Format(doc.text)
+	if err != nil {
+		return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+	if formatted == doc.text {
+		return []TextEdit{}, nil
+	}
+
+	lines := strings.Split(doc.text, "\n")
+	last := lines[len(lines)-1]
+	end := Position{Line: len(lines) - 1, Character: s.character(last, utf8.RuneCountInString(last))}
+	return []TextEdit{{Range: Range{End: end}, NewText: formatted}}, nil
+}
+
+// objectDocument returns an open document that is mapped to a repository object
+func (s *Server) objectDocument(uri string) (*document, *responseError) {
+	doc := s.documents[uri]
+	if doc == nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: "document not open: " + uri}
+	}
+	if doc.object == nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: "not an ABAP object file: " + uri}
+	}
+	return doc, nil
+}
+
+// locate maps a source URI to a document URI: an open document, a workspace file or
+// a copy of the source in the cache directory
+func (s *Server) locate(sourceURI string) (string, error) {
+	for _, doc := range s.documents {
+		if doc.object != nil && strings.EqualFold(doc.object.SourceURI, sourceURI) {
+			return doc.uri, nil
+		}
+	}
+
+	if path, ok := s.workspaceIndex()[strings.ToLower(sourceURI)]; ok {
+		return pathToURI(path), nil
+	}
+
+	if s.options.CacheDir == "" {
+		return "", fmt.Errorf("definition %s is outside the workspace", sourceURI)
+	}
+
+	source, err := s.adtClient.GetSourceByURI(sourceURI)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.options.CacheDir, filepath.FromSlash(strings.ToLower(strings.TrimPrefix(sourceURI, "/sap/bc/adt/")))) + ".abap"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create source cache: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(source.Source), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return pathToURI(path), nil
+}
+
+// workspaceIndex maps the source URIs of all ABAP files in the workspace to their paths
+func (s *Server) workspaceIndex() map[string]string {
+	if s.index != nil {
+		return s.index
+	}
+
+	s.index = make(map[string]string)
+	if s.rootPath == "" {
+		return s.index
+	}
+
+	filepath.WalkDir(s.rootPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") && path != s.rootPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if object, err := s.resolve(path); err == nil && object != nil {
+			s.index[strings.ToLower(object.SourceURI)] = path
+		}
+		return nil
+	})
+
+	s.logger.Info("Workspace indexed", zap.String("root", s.rootPath), zap.Int("sources", len(s.index)))
+	return s.index
+}
+
+// reply sends the response to a request
+func (s *Server) reply(id json.RawMessage, result interface{}, rpcErr *responseError) {
+	resp := response{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &responseError{Code: codeInternalError, Message: err.Error()}
+		} else {
+			resp.Result = data
+		}
+	}
+
+	if err := s.conn.write(resp); err != nil {
+		s.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method string, params interface{}) {
+	if err := s.conn.write(notification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		s.logger.Error("Failed to write notification", zap.String("method", method), zap.Error(err))
+	}
+}
+
+// showMessage displays a message in the editor
+func (s *Server) showMessage(messageType int, text string) {
+	s.notify("window/showMessage", map[string]interface{}{"type": messageType, "message": text})
+}
+
+// decode unmarshals request parameters
+func decode(params json.RawMessage, v interface{}) *responseError {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// tokenRange returns the range of the word starting at line and character column
+func (s *Server) tokenRange(lines []string, line, column int) Range {
+	if line < 0 {
+		line = 0
+	}
+	if column < 0 {
+		column = 0
+	}
+	text := ""
+	if line < len(lines) {
+		text = strings.TrimRight(lines[line], "\r")
+	}
+	runes := []rune(text)
+	end := column
+	for end < len(runes) && runes[end] != ' ' {
+		end++
+	}
+	return Range{
+		Start: Position{Line: line, Character: s.character(text, column)},
+		End:   Position{Line: line, Character: s.character(text, end)},
+	}
+}
+
+// character converts a character column of a line, as ADT counts them, to a position
+// character in the agreed encoding. Columns past the end of the line count one unit each.
+func (s *Server) character(line string, column int) int {
+	units := 0
+	for i := 0; i < len(line) && column > 0; column-- {
+		size, n := s.units(line[i:])
+		units += n
+		i += size
+	}
+	return units + column
+}
+
+// column converts a position character in the agreed encoding to a character column of a
+// line. A position inside a character refers to the character after it.
+func (s *Server) column(line string, character int) int {
+	column, units := 0, 0
+	for i := 0; i < len(line) && units < character; column++ {
+		size, n := s.units(line[i:])
+		units += n
+		i += size
+	}
+	if units < character {
+		column += character - units
+	}
+	return column
+}
+
+// units returns the size in bytes and in the agreed encoding of the first character of
+// text. Invalid bytes are characters of their own.
+func (s *Server) units(text string) (int, int) {
+	r, size := utf8.DecodeRuneInString(text)
+	if s.utf8 {
+		return size, size
+	}
+	if r == utf8.RuneError && size == 1 {
+		return size, 1
+	}
+	return size, utf16.RuneLen(r)
+}
+
+// documentLine returns a line of an open document or of a file, or an empty string
+func (s *Server) documentLine(uri string, line int) string {
+	if doc := s.documents[uri]; doc != nil {
+		return lineAt(doc.text, line)
+	}
+	path := uriToPath(uri)
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return lineAt(string(data), line)
+}
+
+// lineAt returns a line of a text without its line ending, or an empty string past the end
+func lineAt(text string, line int) string {
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[line], "\r")
+}
+
+// sameSource compares sources regardless of line endings and trailing newlines
+func sameSource(a, b string) bool {
+	normalize := func(source string) string {
+		return strings.TrimRight(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	}
+	return normalize(a) == normalize(b)
+}
+
+// uriToPath converts a file URI to a local path, other schemes return an empty path
+func uriToPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(parsed.Path)
+}
+
+// pathToURI converts a local path to a file URI
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,88 @@
+package lsp
+
+import "testing"
+
+// line with a two byte character (one UTF-16 unit) and a four byte character (two UTF-16 units)
+const mixedLine = "  x = 'ä😀'. WRITE y."
+
+func TestCharacterAndColumn(t *testing.T) {
+	tests := []struct {
+		name      string
+		utf8      bool
+		column    int // character column as ADT counts them
+		character int // position character in the negotiated encoding
+	}{
+		{"utf-16 start", false, 0, 0},
+		{"utf-16 before umlaut", false, 7, 7},
+		{"utf-16 after umlaut", false, 8, 8},
+		{"utf-16 after surrogate pair", false, 9, 10},
+		{"utf-16 keyword after non-ASCII", false, 12, 13},
+		{"utf-16 end of line", false, 20, 21},
+		{"utf-16 past end of line", false, 22, 23},
+		{"utf-8 before umlaut", true, 7, 7},
+		{"utf-8 after umlaut", true, 8, 9},
+		{"utf-8 after emoji", true, 9, 13},
+		{"utf-8 keyword after non-ASCII", true, 12, 16},
+		{"utf-8 past end of line", true, 22, 26},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{utf8: tt.utf8}
+			if got := s.character(mixedLine, tt.column); got != tt.character {
+				t.Errorf("character(%d) = %d, want %d", tt.column, got, tt.character)
+			}
+			if got := s.column(mixedLine, tt.character); got != tt.column {
+				t.Errorf("column(%d) = %d, want %d", tt.character, got, tt.column)
+			}
+		})
+	}
+}
+
+func TestColumnInsideCharacter(t *testing.T) {
+	// The second unit of the surrogate pair and the second byte of the umlaut refer to the next character
+	if got := (&Server{}).column(mixedLine, 9); got != 9 {
+		t.Errorf("utf-16 column(9) = %d, want 9", got)
+	}
+	if got := (&Server{utf8: true}).column(mixedLine, 8); got != 8 {
+		t.Errorf("utf-8 column(8) = %d, want 8", got)
+	}
+}
+
+func TestCharacterInvalidUTF8(t *testing.T) {
+	line := "a\xffb"
+	for _, s := range []*Server{{}, {utf8: true}} {
+		if got := s.character(line, 2); got != 2 {
+			t.Errorf("character(2) with utf8=%v = %d, want 2", s.utf8, got)
+		}
+		if got := s.column(line, 2); got != 2 {
+			t.Errorf("column(2) with utf8=%v = %d, want 2", s.utf8, got)
+		}
+	}
+}
+
+func TestTokenRange(t *testing.T) {
+	lines := []string{"REPORT ztest.", "* ä WRITE foo.\r"}
+	tests := []struct {
+		name   string
+		utf8   bool
+		line   int
+		column int
+		want   Range
+	}{
+		{"ascii line", false, 0, 7, Range{Start: Position{0, 7}, End: Position{0, 13}}},
+		{"utf-16 after umlaut", false, 1, 4, Range{Start: Position{1, 4}, End: Position{1, 9}}},
+		{"utf-8 after umlaut", true, 1, 4, Range{Start: Position{1, 5}, End: Position{1, 10}}},
+		{"negative position", false, -1, -1, Range{Start: Position{0, 0}, End: Position{0, 6}}},
+		{"past last line", false, 5, 2, Range{Start: Position{5, 2}, End: Position{5, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{utf8: tt.utf8}
+			if got := s.tokenRange(lines, tt.line, tt.column); got != tt.want {
+				t.Errorf("tokenRange(%d, %d) = %+v, want %+v", tt.line, tt.column, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,18 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/bluefunda/abaper/lsp"
+	"github.com/bluefunda/abaper/mcp"
+	"github.com/bluefunda/abaper/reports"
 	"github.com/bluefunda/abaper/rest/server"
 	"github.com/bluefunda/abaper/types"
 	"github.com/spf13/cobra"
@@ -49,6 +55,19 @@ type Config struct {
 	ADTUsername string
 	ADTPassword string
 
+	// System profile settings
+	System           string // Profile selected with --system
+	Language         string
+	AllowSelfSigned  bool
+	CAFile           string
+	ConnectTimeout   int    // seconds
+	RequestTimeout   int    // seconds
+	Credentials      string // Credential backend
+	CredentialHelper string // Credential helper command
+	PasswordFile     string // Pass-style password file
+	SessionTimeout   int    // Minutes an unused login session is reused
+	NoSession        bool   // Do not reuse or store login sessions
+
 	// File logging support
 	LogFile    string
 	ConfigFile string
@@ -67,6 +86,68 @@ var (
 	cachedADTConfig string
 	cacheTime       time.Time
 	cacheTimeout    = 30 * time.Minute
+
+	// Command specific flags
+	getRecursive         bool
+	getInclude           string
+	searchMaxResults     int
+	searchOffset         int
+	listDetails          bool
+	putTransport         string
+	putETag              string
+	putForce             bool
+	checkFile            string
+	reportFormat         string
+	reportOutput         string
+	atcOptions           = &ATCOptions{}
+	testFormat           string
+	testOutput           string
+	pullOutDir           string
+	pushPackage          string
+	createPackage        string
+	createDescription    string
+	createTransport      string
+	createSource         string
+	deleteTransport      string
+	deleteForce          bool
+	deleteYes            bool
+	transportPackage     string
+	releaseTasks         bool
+	whereUsedDepth       int
+	navLine              int
+	navColumn            int
+	navFile              string
+	pushTransport        string
+	lspTransport         string
+	lspActivate          bool
+	mcpHTTPAddr          string
+	mcpAllowWrites       bool
+	mcpToken             string
+	fmtCheck             bool
+	historyInclude       string
+	diffFrom             string
+	diffTo               string
+	diffInclude          string
+	compareLeft          string
+	compareRight         string
+	compareRecursive     bool
+	compareShowDiff      bool
+	configHost           string
+	configClient         string
+	configLanguage       string
+	configUser           string
+	configInsecure       bool
+	configCAFile         string
+	configConnectTimeout int
+	configSessionTimeout int
+	configCredentials    string
+	configHelper         string
+	configPasswordFile   string
+	loginPasswordStdin   bool
+	loginNoVerify        bool
+	configRequestTimeout int
+	configPackage        string
+	configTransport      string
 )
 
 // Root command
@@ -79,9 +160,21 @@ A comprehensive CLI tool for interacting with SAP ABAP systems via ADT.
 Supports retrieving source code, searching objects, and testing connections.`,
 	Version: Version,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Stdio protocol servers own stdout, nothing else may be printed there
+		if cmd.Annotations["stdio"] == "true" {
+			rootConfig.Quiet, rootConfig.Normal = true, false
+		}
+
 		// Initialize logger
 		initLogger(rootConfig.Verbose, rootConfig.Quiet && !rootConfig.Normal, rootConfig.LogFile)
 
+		// Config commands manage the profiles themselves
+		if cmd != configCmd {
+			if err := selectSystem(cmd); err != nil {
+				return err
+			}
+		}
+
 		// Setup signal handling
 		setupSignalHandling()
 
@@ -102,47 +195,715 @@ Supports retrieving source code, searching objects, and testing connections.`,
 	},
 }
 
-// Server command
-var serverCmd = &cobra.Command{
-	Use:   "server",
-	Short: "Run as REST API server",
-	Long:  "Start the ABAPER REST API server for HTTP-based ABAP operations.",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		rootConfig.Mode = "server"
-		return runServerMode(rootConfig)
+// Server command
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Run as REST API server",
+	Long:  "Start the ABAPER REST API server for HTTP-based ABAP operations.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "server"
+		return runServerMode(rootConfig)
+	},
+}
+
+// LSP command
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run as language server over stdio",
+	Long: `Start a Language Server Protocol server on stdin/stdout for editors like
+Neovim, Zed, Helix or VS Code.
+
+Files are mapped to repository objects by their abapGit file names, e.g.
+ztest.prog.abap, zcl_test.clas.abap or zcl_test.clas.testclasses.abap.
+
+- Opening a file compares it with the system version and reports syntax check findings
+- Saving writes the file to the system (and activates it with --activate)
+- Completion, go-to-definition and formatting are answered by the system
+
+Definitions outside the workspace are downloaded to the user cache directory.
+
+EXAMPLES:
+  abaper lsp
+  abaper lsp --transport DEVK900123 --activate
+
+  # Neovim
+  vim.lsp.start({ name = "abaper", cmd = { "abaper", "lsp" }, root_dir = vim.fs.root(0, ".abapgit.xml") })`,
+	Annotations: map[string]string{"stdio": "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "lsp"
+		return runLSPMode(rootConfig)
+	},
+}
+
+// MCP command
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run as Model Context Protocol server",
+	Long: `Start a Model Context Protocol server that exposes ADT operations as tools
+for AI assistants. Messages are served on stdin/stdout, or with --http on the
+streamable HTTP transport at /mcp.
+
+TOOLS (read-only):
+  get_source         Source of a program, class, interface, function module, ...
+  search_objects     Search objects by name pattern
+  package_contents   Objects and subpackages of a package
+  where_used         Objects using an object
+  syntax_check       Syntax check of an object or a modified source
+  table_contents     Rows of a database table
+
+With --allow-writes the update_source and activate tools are exposed as well.
+
+Over HTTP, requests from browser pages are only accepted from localhost. Binding
+to an address other than the loopback interface requires --token; clients then
+send it as "Authorization: Bearer TOKEN".
+
+EXAMPLES:
+  abaper mcp
+  abaper mcp --http 127.0.0.1:8765
+  abaper mcp --http 0.0.0.0:8765 --token "$(openssl rand -hex 32)"
+  abaper mcp --allow-writes`,
+	Annotations: map[string]string{"stdio": "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "mcp"
+		return runMCPMode(rootConfig)
+	},
+}
+
+// Get command
+var getCmd = &cobra.Command{
+	Use:   "get TYPE NAME [ARGS...]",
+	Short: "Retrieve ABAP object source code",
+	Long: `Retrieve ABAP object source code from SAP system.
+
+TYPES:
+  program     ABAP program/report
+  class       ABAP class
+  function    ABAP function module (requires function group)
+  include     ABAP include
+  interface   ABAP interface
+  structure   ABAP structure
+  table       ABAP table
+  package     ABAP package contents
+
+EXAMPLES:
+  abaper get program ZTEST
+  abaper get class ZCL_TEST
+  abaper get class ZCL_TEST --include testclasses
+  abaper get class ZCL_TEST --include all
+  abaper get function ZTEST_FUNC ZTEST_GROUP
+  abaper get package $TMP
+  abaper get package ZDEV --recursive`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "get",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			Recursive:  getRecursive,
+			Include:    getInclude,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleGet(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Put command
+var putCmd = &cobra.Command{
+	Use:   "put TYPE NAME [ARGS...] FILE",
+	Short: "Write local source code back to an ABAP object",
+	Long: `Write local source code back to an ABAP object.
+
+The object is locked, the source is uploaded and the lock is always released
+afterwards. The object is left inactive.
+
+Pass the version printed by get (or by the previous put) with --etag: the
+upload is refused if the server version differs, so changes made on the server
+since are not overwritten. Without --etag the upload requires --force.
+
+TYPES:
+  program     ABAP program/report
+  class       ABAP class
+  function    ABAP function module (requires function group)
+  include     ABAP include
+  interface   ABAP interface
+
+EXAMPLES:
+  abaper put program ZTEST ztest.abap --etag 202410161200000011
+  abaper put class ZCL_TEST zcl_test.abap --etag 202410161200000012 --transport DEVK900123
+  abaper put function ZTEST_FUNC ZTEST_GROUP ztest_func.abap --etag 202410161200000013
+  abaper put program ZTEST ztest.abap --force`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "put",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2 : len(args)-1],
+			FilePath:   args[len(args)-1],
+			Transport:  putTransport,
+			ETag:       putETag,
+			Force:      putForce,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandlePut(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Create command
+var createCmd = &cobra.Command{
+	Use:   "create TYPE NAME [ARGS...]",
+	Short: "Create a new ABAP object",
+	Long: `Create a new ABAP object in a package.
+
+The object is created empty unless an initial source file is given, which is
+uploaded right after creation. The object is left inactive.
+
+TYPES:
+  program          ABAP program/report
+  include          ABAP include
+  class            ABAP class
+  interface        ABAP interface
+  function_group   ABAP function group
+  function         ABAP function module (requires function group, no package)
+
+EXAMPLES:
+  abaper create program ZTEST --package $TMP --description "Test report"
+  abaper create class ZCL_TEST --package ZDEV --transport DEVK900123 --source zcl_test.abap
+  abaper create function_group ZTEST_GROUP --package ZDEV
+  abaper create function ZTEST_FUNC ZTEST_GROUP --description "Test function"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:      "create",
+			ObjectType:  args[0],
+			ObjectName:  args[1],
+			Args:        args[2:],
+			FilePath:    createSource,
+			Package:     createPackage,
+			Description: createDescription,
+			Transport:   createTransport,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleCreate(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete TYPE NAME [ARGS...]",
+	Short: "Delete an ABAP object",
+	Long: `Delete an ABAP object.
+
+Before deleting, a where-used check is run and the deletion is refused while
+other objects still use the object (skip the check with --force). You are
+asked for confirmation unless --yes is given.
+
+TYPES:
+  program          ABAP program/report
+  include          ABAP include
+  class            ABAP class
+  interface        ABAP interface
+  function_group   ABAP function group
+  function         ABAP function module (requires function group)
+
+EXAMPLES:
+  abaper delete program ZTEST_OLD
+  abaper delete class ZCL_OBSOLETE --transport DEVK900123
+  abaper delete function ZTEST_FUNC ZTEST_GROUP --yes
+  abaper delete program ZPROTOTYPE --force --yes`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "delete",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			Transport:  deleteTransport,
+			Force:      deleteForce,
+			AssumeYes:  deleteYes,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleDelete(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Activate command
+var activateCmd = &cobra.Command{
+	Use:   "activate TYPE NAME [NAME...]",
+	Short: "Activate ABAP objects",
+	Long: `Activate one or more ABAP objects of the same type.
+
+Activation errors and warnings are printed with their line numbers. The
+command exits with a non-zero status if activation fails.
+
+EXAMPLES:
+  abaper activate program ZTEST
+  abaper activate class ZCL_TEST ZCL_TEST_HELPER
+  abaper activate function ZTEST_FUNC ZTEST_GROUP
+  abaper activate fugr ZTEST_GROUP
+  abaper list inactive`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "activate",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleActivate(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Check command
+var checkCmd = &cobra.Command{
+	Use:   "check TYPE NAME [ARGS...]",
+	Short: "Run a server-side syntax check",
+	Long: `Run a server-side syntax check for an ABAP object.
+
+With --file the local source is checked instead of the version stored on
+the server, which makes the command usable in pre-commit hooks. Findings
+are printed as FILE:LINE:COLUMN: SEVERITY: MESSAGE and the command exits
+with a non-zero status when errors are found.
+
+EXAMPLES:
+  abaper check program ZTEST
+  abaper check class ZCL_TEST --file zcl_test.abap
+  abaper check function ZTEST_FUNC ZTEST_GROUP --file ztest_func.abap`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "check",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			FilePath:   checkFile,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleCheck(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Fmt command
+var fmtCmd = &cobra.Command{
+	Use:   "fmt FILE|DIR...",
+	Short: "Format ABAP source files",
+	Long: `Format local ABAP source files with the pretty printer of the system, using
+your formatter settings (keyword case and indentation).
+
+Directories are searched for .abap files, e.g. an abapGit checkout. With
+--check the files are not changed; unformatted files are listed and the
+command exits with a non-zero status, which makes it usable in CI.
+
+EXAMPLES:
+  abaper fmt ztest.prog.abap
+  abaper fmt src/
+  abaper fmt src/ --check`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action: "fmt",
+			Args:   args,
+			Check:  fmtCheck,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleFmt(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// ATC command
+var atcCmd = &cobra.Command{
+	Use:   "atc TYPE NAME [ARGS...]",
+	Short: "Run ABAP Test Cockpit checks",
+	Long: `Run ABAP Test Cockpit (ATC) checks for an object or a package.
+
+A worklist is created for the check variant (the system default if
+--variant is not given), the run is started and the command waits for it
+to complete. Findings are printed as text or written as a checkstyle, SARIF
+or JUnit report. The command exits with a non-zero status if findings of
+the --fail-on priority or higher exist.
+
+EXAMPLES:
+  abaper atc class ZCL_TEST
+  abaper atc package ZDEV --variant DEFAULT
+  abaper atc package ZDEV --format sarif --output atc.sarif
+  abaper atc program ZTEST --format junit --fail-on 2`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "atc",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			Format:     reportFormat,
+			OutputPath: reportOutput,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleATC(config, atcOptions, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Test command
+var testCmd = &cobra.Command{
+	Use:   "test TYPE NAME [ARGS...]",
+	Short: "Run ABAP Unit tests",
+	Long: `Run ABAP Unit tests for an object or a package.
+
+Results are printed per test class and method, or written as JUnit XML with
+--format junit. The command exits with a non-zero status if any test fails.
+
+EXAMPLES:
+  abaper test class ZCL_TEST
+  abaper test package ZDEV
+  abaper test class ZCL_TEST --format junit --output TEST-abap.xml`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "test",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			Format:     testFormat,
+			OutputPath: testOutput,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleTest(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Pull command
+var pullCmd = &cobra.Command{
+	Use:   "pull PACKAGE",
+	Short: "Export a package into an abapGit directory layout",
+	Long: `Export a package and its subpackages into a local directory in abapGit layout.
+
+Every supported object (programs, includes, classes with their local and
+test class includes, interfaces and function groups) is downloaded and written with abapGit file
+names and an XML metadata file per object. Subpackages become folders using
+abapGit's PREFIX folder logic.
+
+EXAMPLES:
+  abaper pull ZDEV
+  abaper pull ZDEV --out ./zdev-repo`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "pull",
+			ObjectType: "package",
+			ObjectName: args[0],
+			OutputPath: pullOutDir,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandlePull(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Push command
+var pushCmd = &cobra.Command{
+	Use:   "push DIR",
+	Short: "Write a local abapGit directory back to the system",
+	Long: `Write a local directory in abapGit layout back to the system.
+
+Each source file is compared with the current server version. Only changed
+objects are locked, updated and unlocked; objects missing on the server are
+created in the package derived from their folder. All changed objects are
+activated together and a result is reported per file.
+
+EXAMPLES:
+  abaper push ./zdev-repo --package ZDEV --transport DEVK900123
+  abaper push ./zdev-repo/src --package $TMP`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:    "push",
+			FilePath:  args[0],
+			Package:   pushPackage,
+			Transport: pushTransport,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandlePush(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Search command
+var searchCmd = &cobra.Command{
+	Use:   "search objects PATTERN [TYPES...]",
+	Short: "Search for ABAP objects",
+	Long: `Search for ABAP objects by pattern.
+
+EXAMPLES:
+  abaper search objects "Z*"
+  abaper search objects "CL_*" class
+  abaper search objects "*TEST*" program class
+  abaper search objects "Z*" --max-results 50 --offset 50`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		if args[0] != "objects" {
+			return fmt.Errorf("search type must be 'objects'")
+		}
+
+		config := &CommandConfig{
+			Action:     "search",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			MaxResults: searchMaxResults,
+			Offset:     searchOffset,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleSearch(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// List command
+var listCmd = &cobra.Command{
+	Use:   "list TYPE [PATTERN]",
+	Short: "List objects of specified type",
+	Long: `List objects of specified type.
+
+TYPES:
+  packages    List packages
+  inactive    List your inactive objects
+
+EXAMPLES:
+  abaper list packages
+  abaper list packages "Z*"
+  abaper list packages "ZSALES*" --details
+  abaper list inactive`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "list",
+			ObjectType: args[0],
+			Details:    listDetails,
+		}
+
+		if len(args) > 1 {
+			config.ObjectName = args[1]
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleList(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Where-used command
+var whereUsedCmd = &cobra.Command{
+	Use:   "where-used TYPE NAME [ARGS...]",
+	Short: "Show where an ABAP object is used",
+	Long: `Show the objects that use an ABAP object as a tree, including the
+source lines of each usage.
+
+With --depth greater than 1 the usages of the using objects are listed as
+well, which helps with impact analysis of shared objects.
+
+EXAMPLES:
+  abaper where-used structure ZSALES_ITEM
+  abaper where-used class ZCL_SALES_HELPER --depth 2
+  abaper where-used function Z_CALCULATE_TAX Z_TAX_GROUP`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "where-used",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			Depth:      whereUsedDepth,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleWhereUsed(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Nav command
+var navCmd = &cobra.Command{
+	Use:   "nav TYPE NAME [ARGS...] --line LINE --column COLUMN",
+	Short: "Go to the definition of a symbol",
+	Long: `Resolve the symbol at a source position to its defining object and position.
+
+Lines are 1-based and columns 0-based, the same positions the check command
+reports. Use --file to navigate in a local (unsaved) version of the source.
+
+EXAMPLES:
+  abaper nav program ZTEST --line 12 --column 8
+  abaper nav class ZCL_SALES_HELPER --line 40 --column 14 --file zcl_sales_helper.abap
+  abaper nav function Z_CALCULATE_TAX Z_TAX_GROUP --line 5 --column 10`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "nav",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			FilePath:   navFile,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleNav(config, navLine, navColumn, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// History command
+var historyCmd = &cobra.Command{
+	Use:   "history TYPE NAME [ARGS...]",
+	Short: "Show the version history of an object",
+	Long: `Show the stored versions of an object source with author, date and the
+transport request each version was stamped with.
+
+EXAMPLES:
+  abaper history program ZTEST
+  abaper history class ZCL_SALES_HELPER --include implementations
+  abaper history function Z_CALCULATE_TAX Z_TAX_GROUP`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rootConfig.Mode = "cli"
+
+		config := &CommandConfig{
+			Action:     "history",
+			ObjectType: args[0],
+			ObjectName: args[1],
+			Args:       args[2:],
+			Include:    historyInclude,
+		}
+
+		adtClient, err := getCachedADTClient(rootConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create ADT client: %w", err)
+		}
+
+		return HandleHistory(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
 	},
 }
 
-// Get command
-var getCmd = &cobra.Command{
-	Use:   "get TYPE NAME [ARGS...]",
-	Short: "Retrieve ABAP object source code",
-	Long: `Retrieve ABAP object source code from SAP system.
+// Diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff TYPE NAME [ARGS...] --from VERSION [--to VERSION]",
+	Short: "Show a unified diff between two versions of an object",
+	Long: `Show a unified diff between two versions of an object source.
 
-TYPES:
-  program     ABAP program/report
-  class       ABAP class
-  function    ABAP function module (requires function group)
-  include     ABAP include
-  interface   ABAP interface
-  structure   ABAP structure
-  table       ABAP table
-  package     ABAP package contents
+A version is "active", "inactive", a version ID from the history command or a
+transport request number (its latest version). --to defaults to active.
 
 EXAMPLES:
-  abaper get program ZTEST
-  abaper get class ZCL_TEST
-  abaper get function ZTEST_FUNC ZTEST_GROUP
-  abaper get package $TMP`,
+  abaper diff program ZTEST --from 00003
+  abaper diff class ZCL_SALES_HELPER --from DEVK900123 --to inactive
+  abaper diff class ZCL_SALES_HELPER --include testclasses --from 00001 --to 00004`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		rootConfig.Mode = "cli"
 
 		config := &CommandConfig{
-			Action:     "get",
+			Action:     "diff",
 			ObjectType: args[0],
 			ObjectName: args[1],
 			Args:       args[2:],
+			Include:    diffInclude,
+			From:       diffFrom,
+			To:         diffTo,
 		}
 
 		adtClient, err := getCachedADTClient(rootConfig)
@@ -150,67 +911,103 @@ EXAMPLES:
 			return fmt.Errorf("failed to create ADT client: %w", err)
 		}
 
-		return HandleGet(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+		return HandleDiff(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
 	},
 }
 
-// Search command
-var searchCmd = &cobra.Command{
-	Use:   "search objects PATTERN [TYPES...]",
-	Short: "Search for ABAP objects",
-	Long: `Search for ABAP objects by pattern.
+// Compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare TYPE NAME [ARGS...] --left SYSTEM --right SYSTEM",
+	Short: "Compare objects between two SAP systems",
+	Long: `Compare the source of an object, or of all objects in a package, between two
+SAP systems. Objects are shown as a unified diff; packages as a summary of
+differing, missing and identical objects.
+
+A system is a profile from the config file (see 'abaper config'). Passwords are
+read from each profile's password file or credential store (see 'abaper login').
 
 EXAMPLES:
-  abaper search objects "Z*"
-  abaper search objects "CL_*" class
-  abaper search objects "*TEST*" program class`,
+  abaper compare program ZTEST --left dev --right qas
+  abaper compare class ZCL_SALES_HELPER --left dev --right prd
+  abaper compare function Z_CALCULATE_TAX Z_TAX_GROUP --left dev --right qas
+  abaper compare package ZSALES --left dev --right qas --recursive
+  abaper compare package ZSALES --left dev --right qas --diff`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		rootConfig.Mode = "cli"
 
-		if args[0] != "objects" {
-			return fmt.Errorf("search type must be 'objects'")
-		}
-
 		config := &CommandConfig{
-			Action:     "search",
+			Action:     "compare",
 			ObjectType: args[0],
 			ObjectName: args[1],
 			Args:       args[2:],
+			Left:       compareLeft,
+			Right:      compareRight,
+			Recursive:  compareRecursive,
+			ShowDiff:   compareShowDiff,
 		}
 
-		adtClient, err := getCachedADTClient(rootConfig)
+		leftConfig, err := systemConfig(compareLeft)
 		if err != nil {
-			return fmt.Errorf("failed to create ADT client: %w", err)
+			return err
+		}
+		rightConfig, err := systemConfig(compareRight)
+		if err != nil {
+			return err
 		}
 
-		return HandleSearch(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+		left, err := CreateADTClient(leftConfig)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", compareLeft, err)
+		}
+		right, err := CreateADTClient(rightConfig)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", compareRight, err)
+		}
+
+		return HandleCompare(config, left, right, rootConfig.Quiet, rootConfig.Normal)
 	},
 }
 
-// List command
-var listCmd = &cobra.Command{
-	Use:   "list TYPE [PATTERN]",
-	Short: "List objects of specified type",
-	Long: `List objects of specified type.
-
-TYPES:
-  packages    List packages
+// Transport command
+var transportCmd = &cobra.Command{
+	Use:   "transport ACTION [ARGS...]",
+	Short: "Manage transport requests",
+	Long: `List, create, check, assign and release transport requests.
+
+ACTIONS:
+  list                        List your modifiable requests with their tasks
+  objects TRKORR              List the objects of a request and its tasks
+  create DESCRIPTION [TYPE NAME [GROUP]]
+                              Create a workbench request (requires --package),
+                              optionally recording an object in it
+  check TYPE NAME [GROUP]     Show which request changes to an object need
+  assign TRKORR TYPE NAME [GROUP]
+                              Record an object in a request or task
+  release TRKORR              Release a request or task
 
 EXAMPLES:
-  abaper list packages
-  abaper list packages "Z*"`,
+  abaper transport list
+  abaper transport objects DEVK900123
+  abaper transport create "Sales report fixes" --package ZSALES
+  abaper transport create "Helper rework" class ZCL_SALES_HELPER --package ZSALES
+  abaper transport check class ZCL_SALES_HELPER
+  abaper transport assign DEVK900124 class ZCL_SALES_HELPER
+  abaper transport release DEVK900123 --tasks`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		rootConfig.Mode = "cli"
 
 		config := &CommandConfig{
-			Action:     "list",
-			ObjectType: args[0],
+			Action:       "transport",
+			ObjectType:   args[0],
+			Package:      transportPackage,
+			ReleaseTasks: releaseTasks,
 		}
 
 		if len(args) > 1 {
 			config.ObjectName = args[1]
+			config.Args = args[2:]
 		}
 
 		adtClient, err := getCachedADTClient(rootConfig)
@@ -218,7 +1015,79 @@ EXAMPLES:
 			return fmt.Errorf("failed to create ADT client: %w", err)
 		}
 
-		return HandleList(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+		return HandleTransport(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Config command
+var configCmd = &cobra.Command{
+	Use:   "config ACTION [NAME]",
+	Short: "Manage named system profiles",
+	Long: `Manage the named SAP systems in the configuration file
+(~/.config/abaper/config.yaml unless --config is given).
+
+Select a system for any command with --system NAME or ABAPER_SYSTEM. Without
+them the current system is used, unless a host is given with --adt-host or
+SAP_HOST. Flags always take precedence over the profile; passwords are not
+stored in profiles.
+
+ACTIONS:
+  list                        List the systems, the current one marked with *
+  add NAME                    Add a system, or change the given settings of one
+  remove NAME                 Remove a system
+  use NAME                    Make a system the current one
+
+EXAMPLES:
+  abaper config add dev --host sapdev.example.com:44300 --client 100 --user DEVELOPER
+  abaper config add qas --host https://sapqas.example.com --ca-file corp-ca.pem
+  abaper config add sbx --host https://sapsbx.example.com --insecure
+  abaper config add dev --package ZSALES --transport DEVK900123
+  abaper config use qas
+  abaper config list
+  abaper get program ZTEST --system qas`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return HandleConfig(cmd, args, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Login command
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store the password of a system",
+	Long: `Log on to a system and store the password in a credential backend, so it
+no longer has to be passed with --adt-password or SAP_PASSWORD.
+
+The backend is taken from the system profile (see 'abaper config'), or from
+ABAPER_CREDENTIALS:
+  auto              Secret Service if secret-tool is available, otherwise file
+  secret-service    GNOME Keyring, KWallet or another Secret Service via secret-tool
+  file              Encrypted keyring file next to the config file; the passphrase
+                    is prompted for or read from ABAPER_KEYRING_PASSPHRASE
+  pass              The standard unix password manager, entries below abaper/
+  helper            External command speaking git's credential helper protocol
+
+EXAMPLES:
+  abaper login --system dev
+  abaper login --adt-host sapdev:8000 --adt-username DEVELOPER
+  echo "$SAP_PASSWORD" | abaper login --system qas --password-stdin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return HandleLogin(rootConfig, loginPasswordStdin, !loginNoVerify, rootConfig.Quiet, rootConfig.Normal)
+	},
+}
+
+// Logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored password of a system",
+	Long: `Remove the password stored by 'abaper login' from the credential backend.
+
+EXAMPLES:
+  abaper logout --system dev`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return HandleLogout(rootConfig, rootConfig.Quiet, rootConfig.Normal)
 	},
 }
 
@@ -264,6 +1133,81 @@ func set_host() string {
 	}
 }
 
+// selectSystem applies the profile chosen with --system. Without --system the current
+// profile is used unless a host is given with --adt-host or SAP_HOST. Connection
+// settings given as flags always take precedence, and the profile's default package
+// and transport fill the command's --package and --transport flags.
+func selectSystem(cmd *cobra.Command) error {
+	profiles, path, err := loadProfiles(rootConfig)
+	if err != nil {
+		return err
+	}
+
+	name, explicit := rootConfig.System, rootConfig.System != ""
+	if !explicit {
+		if profiles.Current == "" || rootConfig.ADTHost != "" {
+			return nil
+		}
+		name = profiles.Current
+	}
+
+	profileName, profile, ok := profiles.lookup(name)
+	if !ok {
+		return fmt.Errorf("system %s not found in %s (see '%s config list')", name, path, PROGRAM_NAME)
+	}
+	name = profileName
+
+	// Flags win; without --system the SAP_* environment wins over the current profile too
+	keep := map[string]bool{
+		"host":   cmd.Flags().Changed("adt-host"),
+		"client": cmd.Flags().Changed("adt-client") || (!explicit && os.Getenv("SAP_CLIENT") != ""),
+		"user":   cmd.Flags().Changed("adt-username") || (!explicit && os.Getenv("SAP_USERNAME") != ""),
+	}
+	profile.applyTo(rootConfig, keep)
+	rootConfig.System = name
+
+	// A password from the flag or SAP_PASSWORD belongs to the host given the same way; the
+	// password of the profile's host comes from its password file or credential store
+	if !keep["host"] {
+		if cmd.Flags().Changed("adt-password") {
+			return fmt.Errorf("--adt-password is only used with --adt-host, store the password of system %s with '%s login'", name, PROGRAM_NAME)
+		}
+		rootConfig.ADTPassword = ""
+	}
+
+	for flag, value := range map[string]string{"package": profile.Package, "transport": profile.Transport} {
+		if f := cmd.Flags().Lookup(flag); f != nil && !f.Changed && value != "" {
+			if err := cmd.Flags().Set(flag, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Debug("Using system profile", zap.String("system", name), zap.String("host", rootConfig.ADTHost))
+	return nil
+}
+
+// systemConfig returns the connection settings of a system profile from the config file.
+// The global password belongs to the global host, so the password of the system is read
+// from its own password file or credential store when the client logs on.
+func systemConfig(name string) (*Config, error) {
+	profiles, path, err := loadProfiles(rootConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	profileName, profile, ok := profiles.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("system %s not found in %s (add it with '%s config add %s')", name, path, PROGRAM_NAME, name)
+	}
+
+	config := *rootConfig
+	profile.applyTo(&config, nil)
+	config.System = profileName
+	config.ADTPassword = ""
+	return &config, nil
+}
+
 // Enhanced logger with file support and quiet mode default
 func initLogger(verbose, quiet bool, logFile string) {
 	var outputPaths, errorPaths []string
@@ -398,7 +1342,7 @@ func setupSignalHandling() {
 func getCachedADTClient(config *Config) (types.ADTClient, error) {
 	// Create cache key from config
 	configKey := fmt.Sprintf("%s|%s|%s|%s",
-		config.ADTHost, config.ADTClient, config.ADTUsername, config.ADTPassword)
+		config.ADTHost, config.ADTClient, config.ADTUsername, passwordFingerprint(config.ADTPassword))
 
 	// Check if we have a valid cached client
 	if cachedADTClient != nil &&
@@ -406,16 +1350,11 @@ func getCachedADTClient(config *Config) (types.ADTClient, error) {
 		time.Since(cacheTime) < cacheTimeout &&
 		cachedADTClient.IsAuthenticated() {
 
-		// Optional: Test connection with lightweight ping (can be disabled for performance)
-		if err := cachedADTClient.TestConnection(); err != nil {
-			logger.Info("Cached ADT client failed ping test, creating new client", zap.Error(err))
-			// Continue to create new client
-		} else {
-			logger.Debug("Using cached ADT client",
-				zap.String("host", config.ADTHost),
-				zap.Duration("cache_age", time.Since(cacheTime)))
-			return cachedADTClient, nil
-		}
+		// No ping: an expired session is renewed by the client on the next request
+		logger.Debug("Using cached ADT client",
+			zap.String("host", config.ADTHost),
+			zap.Duration("cache_age", time.Since(cacheTime)))
+		return cachedADTClient, nil
 	}
 
 	// Cache miss or expired - create new client
@@ -485,6 +1424,72 @@ func runServerMode(config *Config) error {
 	return nil
 }
 
+// runLSPMode serves the Language Server Protocol on stdin/stdout
+func runLSPMode(config *Config) error {
+	adtClient, err := getCachedADTClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create ADT client: %w", err)
+	}
+
+	// Sources of definitions outside the workspace are cached per system
+	cacheDir := ""
+	if userCacheDir, err := os.UserCacheDir(); err == nil {
+		host := strings.NewReplacer(":", "_", "/", "_").Replace(config.ADTHost)
+		cacheDir = filepath.Join(userCacheDir, PROGRAM_NAME, "lsp", host)
+	}
+
+	lspServer := lsp.NewServer(adtClient, logger, lspObject, lsp.Options{
+		Transport: lspTransport,
+		Activate:  lspActivate,
+		CacheDir:  cacheDir,
+		Version:   Version,
+	})
+	return lspServer.Run(os.Stdin, os.Stdout)
+}
+
+// runMCPMode serves the Model Context Protocol on stdin/stdout or streamable HTTP
+func runMCPMode(config *Config) error {
+	adtClient, err := getCachedADTClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create ADT client: %w", err)
+	}
+
+	mcpServer := mcp.NewServer(adtClient, logger, mcp.Options{
+		AllowWrites: mcpAllowWrites,
+		Token:       mcpToken,
+		Version:     Version,
+	})
+
+	if mcpHTTPAddr == "" {
+		return mcpServer.ServeStdio(os.Stdin, os.Stdout)
+	}
+
+	host, _, err := net.SplitHostPort(mcpHTTPAddr)
+	if err != nil {
+		return fmt.Errorf("invalid --http address %s: %w", mcpHTTPAddr, err)
+	}
+	if !mcp.IsLoopback(host) && mcpToken == "" {
+		return fmt.Errorf("refusing to serve MCP on %s without a token: bind to 127.0.0.1 or set --token (or ABAPER_MCP_TOKEN)", mcpHTTPAddr)
+	}
+
+	logger.Info("Starting MCP server", zap.String("address", mcpHTTPAddr))
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcpServer)
+	return http.ListenAndServe(mcpHTTPAddr, mux)
+}
+
+// lspObject maps an abapGit source file to the object the language server reads and writes
+func lspObject(path string) (*lsp.Object, error) {
+	if !strings.HasSuffix(path, ".abap") {
+		return nil, nil
+	}
+	item, err := parseAbapGitFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return &lsp.Object{Object: item.Object, SourceURI: item.SourceURI}, nil
+}
+
 // Error handling helper
 func exitWithError(err error, exitCode int) {
 	fmt.Fprintf(os.Stderr, "%s: %v\n", PROGRAM_NAME, err)
@@ -500,10 +1505,40 @@ func executeCommand(config *CommandConfig, adtClient types.ADTClient) error {
 	switch config.Action {
 	case "get":
 		return HandleGet(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "put":
+		return HandlePut(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "create":
+		return HandleCreate(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "delete":
+		return HandleDelete(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "check":
+		return HandleCheck(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "fmt":
+		return HandleFmt(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "pull":
+		return HandlePull(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "push":
+		return HandlePush(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "test":
+		return HandleTest(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "atc":
+		return HandleATC(config, atcOptions, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "activate":
+		return HandleActivate(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
 	case "search":
 		return HandleSearch(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
 	case "list":
 		return HandleList(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "transport":
+		return HandleTransport(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "where-used":
+		return HandleWhereUsed(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "history":
+		return HandleHistory(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "diff":
+		return HandleDiff(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
+	case "nav":
+		return HandleNav(config, navLine, navColumn, adtClient, rootConfig.Quiet, rootConfig.Normal)
 	case "connect":
 		return HandleConnect(config, adtClient, rootConfig.Quiet, rootConfig.Normal)
 	case "help":
@@ -547,9 +1582,32 @@ TYPES:
 EXAMPLES:
   %s get program ZTEST
   %s get class ZCL_TEST
+  %s get class ZCL_TEST --include testclasses
   %s get function ZTEST_FUNC ZTEST_GROUP
   %s get package $TMP
-`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+  %s get package ZDEV --recursive
+
+OPTIONS:
+  -r, --recursive        Walk subpackages (package only)
+      --include INCLUDE  Class include: main, definitions, implementations,
+                         testclasses, macros or all (class only)
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "put":
+		fmt.Printf(`Usage: %s put TYPE NAME [ARGS...] FILE
+
+Write local source code back to an ABAP object (lock, update, unlock).
+
+OPTIONS:
+  -t, --transport TRKORR   Transport request for the change
+      --etag VERSION       Version printed by get, refuse the upload if the server version differs
+      --force              Overwrite the server version without --etag
+
+EXAMPLES:
+  %s put program ZTEST ztest.abap --etag 202410161200000011
+  %s put class ZCL_TEST zcl_test.abap --etag 202410161200000012 --transport DEVK900123
+  %s put function ZTEST_FUNC ZTEST_GROUP ztest_func.abap --force
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
 
 	case "search":
 		fmt.Printf(`Usage: %s search objects PATTERN [TYPES...]
@@ -560,7 +1618,8 @@ EXAMPLES:
   %s search objects "Z*"
   %s search objects "CL_*" class
   %s search objects "*TEST*" program class
-`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+  %s search objects "Z*" --max-results 50 --offset 50
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
 
 	case "list":
 		fmt.Printf(`Usage: %s list TYPE [PATTERN]
@@ -569,12 +1628,280 @@ List objects of specified type.
 
 TYPES:
   packages    List packages
+  inactive    List your inactive objects
+
+OPTIONS:
+      --details   Show software and application component (one request per package)
 
 EXAMPLES:
   %s list packages
   %s list packages "Z*"
+  %s list packages "ZSALES*" --details
+  %s list inactive
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "nav":
+		fmt.Printf(`Usage: %s nav TYPE NAME [ARGS...] --line LINE --column COLUMN [--file FILE]
+
+Resolve the symbol at a source position to its defining object and position.
+
+OPTIONS:
+  -l, --line LINE       Source line (1-based)
+  -c, --column COLUMN   Source column (0-based)
+  -f, --file FILE       Navigate in a local version of the source
+
+EXAMPLES:
+  %s nav program ZTEST --line 12 --column 8
+`, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "fmt":
+		fmt.Printf(`Usage: %s fmt FILE|DIR... [--check]
+
+Format local ABAP source files with the pretty printer of the system.
+
+OPTIONS:
+  --check   List unformatted files and exit non-zero instead of writing
+
+EXAMPLES:
+  %s fmt src/ --check
+`, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "history":
+		fmt.Printf(`Usage: %s history TYPE NAME [ARGS...] [--include INCLUDE]
+
+Show the stored versions of an object source.
+
+OPTIONS:
+  --include INCLUDE   Class include (classes only)
+
+EXAMPLES:
+  %s history program ZTEST
+`, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "diff":
+		fmt.Printf(`Usage: %s diff TYPE NAME [ARGS...] --from VERSION [--to VERSION]
+
+Show a unified diff between two versions of an object source. A version is
+active, inactive, a version ID from history or a transport request number.
+
+OPTIONS:
+  --from VERSION      Version to compare from
+  --to VERSION        Version to compare to (default: active)
+  --include INCLUDE   Class include (classes only)
+
+EXAMPLES:
+  %s diff program ZTEST --from 00003
+`, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "login":
+		fmt.Printf(`Usage: %s login [--system NAME] [--password-stdin] [--no-verify]
+
+Log on and store the password in the system's credential backend
+(auto, secret-service, file, pass or helper). '%s logout' removes it.
+
+OPTIONS:
+  --password-stdin    Read the password from stdin
+  --no-verify         Store the password without logging on
+
+EXAMPLES:
+  %s login --system dev
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "config":
+		fmt.Printf(`Usage: %s config ACTION [NAME] [OPTIONS]
+
+Manage named system profiles in ~/.config/abaper/config.yaml (or --config).
+Select a system with --system NAME or ABAPER_SYSTEM.
+
+ACTIONS:
+  list                List the systems, the current one marked with *
+  add NAME            Add a system, or change the given settings of one
+  remove NAME         Remove a system
+  use NAME            Make a system the current one
+
+OPTIONS (add):
+  --host, --client, --language, --user
+  --insecure, --ca-file, --connect-timeout, --request-timeout, --session-timeout
+  --package, --transport
+  --credentials, --credential-helper, --password-file
+
+EXAMPLES:
+  %s config add dev --host sapdev.example.com:44300 --client 100 --user DEVELOPER
+  %s config use dev
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "compare":
+		fmt.Printf(`Usage: %s compare TYPE NAME [ARGS...] --left SYSTEM --right SYSTEM
+
+Compare an object, or all objects of a package, between two SAP systems.
+A system is a profile from the config file, see '%s config'.
+
+OPTIONS:
+  --left SYSTEM       System to compare from
+  --right SYSTEM      System to compare to
+  --recursive, -r     Include subpackages (package only)
+  --diff              Print the diffs of differing objects (package only)
+
+EXAMPLES:
+  %s compare class ZCL_SALES_HELPER --left dev --right qas
+  %s compare package ZSALES --left dev --right qas --recursive
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "where-used":
+		fmt.Printf(`Usage: %s where-used TYPE NAME [ARGS...] [--depth N]
+
+Show the objects that use an ABAP object as a tree with the source lines of
+each usage.
+
+OPTIONS:
+  -d, --depth N   Levels of nested usages to show (default: 1)
+
+EXAMPLES:
+  %s where-used structure ZSALES_ITEM
+  %s where-used class ZCL_SALES_HELPER --depth 2
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "transport":
+		fmt.Printf(`Usage: %s transport ACTION [ARGS...]
+
+Manage transport requests.
+
+ACTIONS:
+  list                        List your modifiable requests with their tasks
+  objects TRKORR              List the objects of a request and its tasks
+  create DESCRIPTION [TYPE NAME [GROUP]]
+                              Create a workbench request (requires --package),
+                              optionally recording an object in it
+  check TYPE NAME [GROUP]     Show which request changes to an object need
+  assign TRKORR TYPE NAME [GROUP]
+                              Record an object in a request or task
+  release TRKORR              Release a request or task
+
+OPTIONS:
+  -p, --package PACKAGE   Package for create and check
+      --tasks             Release open tasks before the request
+
+EXAMPLES:
+  %s transport list
+  %s transport assign DEVK900124 class ZCL_SALES_HELPER
+  %s transport release DEVK900123 --tasks
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "check":
+		fmt.Printf(`Usage: %s check TYPE NAME [ARGS...] [--file FILE]
+
+Run a server-side syntax check for an ABAP object.
+
+OPTIONS:
+  -f, --file FILE   Check a local source file instead of the server version
+
+EXAMPLES:
+  %s check program ZTEST
+  %s check class ZCL_TEST --file zcl_test.abap
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "atc":
+		fmt.Printf(`Usage: %s atc TYPE NAME [ARGS...]
+
+Run ABAP Test Cockpit checks for an object or a package.
+
+OPTIONS:
+  --variant NAME     Check variant (default: system check variant)
+  --format FORMAT    Output format: text, checkstyle, sarif, junit
+  -o, --output FILE  Write the report to FILE instead of stdout
+  --max-verdicts N   Maximum number of findings (default: 100)
+  --fail-on N        Exit non-zero on findings with priority N or higher (0 disables)
+
+EXAMPLES:
+  %s atc class ZCL_TEST
+  %s atc package ZDEV --variant DEFAULT --format sarif --output atc.sarif
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "test":
+		fmt.Printf(`Usage: %s test TYPE NAME [ARGS...]
+
+Run ABAP Unit tests for an object or a package.
+
+OPTIONS:
+  --format FORMAT    Output format: text, junit
+  -o, --output FILE  Write the report to FILE instead of stdout
+
+EXAMPLES:
+  %s test class ZCL_TEST
+  %s test package ZDEV --format junit --output TEST-abap.xml
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "pull":
+		fmt.Printf(`Usage: %s pull PACKAGE [--out DIR]
+
+Export a package and its subpackages into a local directory in abapGit layout.
+
+OPTIONS:
+  -o, --out DIR   Target directory (default: package name)
+
+EXAMPLES:
+  %s pull ZDEV
+  %s pull ZDEV --out ./zdev-repo
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "push":
+		fmt.Printf(`Usage: %s push DIR --package PACKAGE [--transport TRKORR]
+
+Write a local abapGit directory back to the system. Changed objects are
+updated, missing objects are created and everything changed is activated.
+
+OPTIONS:
+  -p, --package PACKAGE   Root package of the directory (required)
+  -t, --transport TRKORR  Transport request for the changes
+
+EXAMPLES:
+  %s push ./zdev-repo --package ZDEV --transport DEVK900123
+  %s push ./zdev-repo/src --package $TMP
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "create":
+		fmt.Printf(`Usage: %s create TYPE NAME [ARGS...] --package PACKAGE [OPTIONS]
+
+Create a new ABAP object (program, include, class, interface, function_group
+or function). Function modules take their function group instead of a package.
+
+OPTIONS:
+  -p, --package PACKAGE    Package of the new object
+  -d, --description TEXT   Short description (default: object name)
+  -t, --transport TRKORR   Transport request for the new object
+  -s, --source FILE        Upload initial source after creation
+
+EXAMPLES:
+  %s create program ZTEST --package $TMP --description "Test report"
+  %s create function ZTEST_FUNC ZTEST_GROUP --description "Test function"
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
+	case "delete":
+		fmt.Printf(`Usage: %s delete TYPE NAME [ARGS...] [OPTIONS]
+
+Delete an ABAP object after a where-used check and confirmation.
+
+OPTIONS:
+  -t, --transport TRKORR  Transport request for the deletion
+      --force             Delete even if other objects still use the object
+  -y, --yes               Do not ask for confirmation
+
+EXAMPLES:
+  %s delete program ZTEST_OLD
+  %s delete function ZTEST_FUNC ZTEST_GROUP --yes
 `, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
 
+	case "activate":
+		fmt.Printf(`Usage: %s activate TYPE NAME [NAME...]
+
+Activate one or more ABAP objects of the same type.
+
+EXAMPLES:
+  %s activate program ZTEST
+  %s activate class ZCL_TEST ZCL_TEST_HELPER
+  %s activate function ZTEST_FUNC ZTEST_GROUP
+`, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME, PROGRAM_NAME)
+
 	case "connect":
 		fmt.Printf(`Usage: %s connect
 
@@ -599,13 +1926,16 @@ EXAMPLES:
 
 func init() {
 	// Initialize configuration with defaults
+	reports.ToolVersion = Version
 	rootConfig.Mode = "cli"
 	rootConfig.Port = "8080"
 	rootConfig.ADTHost = set_host()
 	rootConfig.ADTClient = os.Getenv("SAP_CLIENT")
 	rootConfig.ADTUsername = os.Getenv("SAP_USERNAME")
 	rootConfig.ADTPassword = os.Getenv("SAP_PASSWORD")
+	rootConfig.Credentials = os.Getenv("ABAPER_CREDENTIALS")
 	rootConfig.Quiet = true // DEFAULT TO QUIET MODE
+	rootConfig.AllowSelfSigned = true
 	rootConfig.LogFile = os.Getenv("ABAPER_LOG_FILE")
 
 	// Add persistent flags
@@ -616,17 +1946,151 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&rootConfig.ADTHost, "adt-host", rootConfig.ADTHost, "SAP system host (or set SAP_HOST)")
 	rootCmd.PersistentFlags().StringVar(&rootConfig.ADTClient, "adt-client", rootConfig.ADTClient, "SAP client (or set SAP_CLIENT)")
 	rootCmd.PersistentFlags().StringVar(&rootConfig.ADTUsername, "adt-username", rootConfig.ADTUsername, "SAP username (or set SAP_USERNAME)")
-	rootCmd.PersistentFlags().StringVar(&rootConfig.ADTPassword, "adt-password", rootConfig.ADTPassword, "SAP password (or set SAP_PASSWORD)")
-	rootCmd.PersistentFlags().StringVar(&rootConfig.ConfigFile, "config", "", "Configuration file path")
+	rootCmd.PersistentFlags().StringVar(&rootConfig.ADTPassword, "adt-password", rootConfig.ADTPassword, "SAP password (visible in the process list, prefer 'abaper login' or SAP_PASSWORD)")
+	rootCmd.PersistentFlags().StringVar(&rootConfig.ConfigFile, "config", "", "Configuration file path (default ~/.config/abaper/config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&rootConfig.NoSession, "no-session", os.Getenv("ABAPER_NO_SESSION") != "", "Log on every time instead of reusing the saved session (or set ABAPER_NO_SESSION)")
+	rootCmd.PersistentFlags().StringVar(&rootConfig.System, "system", os.Getenv("ABAPER_SYSTEM"), "System profile from the config file (or set ABAPER_SYSTEM)")
 
 	// Server command flags
 	serverCmd.Flags().StringVarP(&rootConfig.Port, "port", "p", "8080", "Port for server mode")
 
+	// Get command flags
+	getCmd.Flags().BoolVarP(&getRecursive, "recursive", "r", false, "Walk subpackages (package only)")
+	getCmd.Flags().StringVar(&getInclude, "include", "", "Class include: main, definitions, implementations, testclasses, macros or all (class only)")
+
+	// List command flags
+	listCmd.Flags().BoolVar(&listDetails, "details", false, "Show software and application component of packages (one request per package)")
+
+	// Search command flags
+	searchCmd.Flags().IntVar(&searchMaxResults, "max-results", 100, "Maximum number of results to return")
+	searchCmd.Flags().IntVar(&searchOffset, "offset", 0, "Number of results to skip (paging)")
+
+	// Put command flags
+	putCmd.Flags().StringVarP(&putTransport, "transport", "t", "", "Transport request for the change")
+	putCmd.Flags().StringVar(&putETag, "etag", "", "Version (ETag) the local file is based on, the upload is refused if the server version differs")
+	putCmd.Flags().BoolVar(&putForce, "force", false, "Overwrite the server version without checking the version of the local file")
+
+	// Create command flags
+	createCmd.Flags().StringVarP(&createPackage, "package", "p", "", "Package of the new object")
+	createCmd.Flags().StringVarP(&createDescription, "description", "d", "", "Short description (default: object name)")
+	createCmd.Flags().StringVarP(&createTransport, "transport", "t", "", "Transport request for the new object")
+	createCmd.Flags().StringVarP(&createSource, "source", "s", "", "Upload initial source from this file after creation")
+
+	// Delete command flags
+	deleteCmd.Flags().StringVarP(&deleteTransport, "transport", "t", "", "Transport request for the deletion")
+	deleteCmd.Flags().BoolVar(&deleteForce, "force", false, "Delete even if other objects still use the object")
+	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Do not ask for confirmation")
+
+	// Where-used command flags
+	whereUsedCmd.Flags().IntVarP(&whereUsedDepth, "depth", "d", 1, "Levels of nested usages to show")
+
+	// History command flags
+	historyCmd.Flags().StringVar(&historyInclude, "include", "", "Class include: main, definitions, implementations, testclasses or macros (class only)")
+
+	// Diff command flags
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "Version to compare from: active, inactive, version ID or transport")
+	diffCmd.Flags().StringVar(&diffTo, "to", "active", "Version to compare to: active, inactive, version ID or transport")
+	diffCmd.Flags().StringVar(&diffInclude, "include", "", "Class include: main, definitions, implementations, testclasses or macros (class only)")
+	diffCmd.MarkFlagRequired("from")
+
+	// Config command flags
+	configCmd.Flags().StringVar(&configHost, "host", "", "Host, e.g. sapdev.example.com:44300 or https://sapdev.example.com")
+	configCmd.Flags().StringVar(&configClient, "client", "", "SAP client")
+	configCmd.Flags().StringVar(&configLanguage, "language", "", "Logon language (default EN)")
+	configCmd.Flags().StringVar(&configUser, "user", "", "SAP username")
+	configCmd.Flags().BoolVar(&configInsecure, "insecure", false, "Skip TLS certificate verification, e.g. for self-signed certificates")
+	configCmd.Flags().StringVar(&configCAFile, "ca-file", "", "PEM file with additional trusted certificates")
+	configCmd.Flags().IntVar(&configConnectTimeout, "connect-timeout", 0, "Connect timeout in seconds (default 30)")
+	configCmd.Flags().IntVar(&configRequestTimeout, "request-timeout", 0, "Request timeout in seconds (default 120)")
+	configCmd.Flags().StringVar(&configCredentials, "credentials", "", "Credential backend: auto, secret-service, file, pass or helper")
+	configCmd.Flags().StringVar(&configHelper, "credential-helper", "", "Credential helper command (git protocol), e.g. \"git credential-libsecret\"")
+	configCmd.Flags().StringVar(&configPasswordFile, "password-file", "", "Pass-style password file, the first line is the password")
+	configCmd.Flags().IntVar(&configSessionTimeout, "session-timeout", 0, "Minutes an unused login session is reused (default 20)")
+	configCmd.Flags().StringVar(&configPackage, "package", "", "Default package for new objects")
+	configCmd.Flags().StringVar(&configTransport, "transport", "", "Default transport request for changes")
+
+	// Login command flags
+	loginCmd.Flags().BoolVar(&loginPasswordStdin, "password-stdin", false, "Read the password from stdin")
+	loginCmd.Flags().BoolVar(&loginNoVerify, "no-verify", false, "Store the password without logging on to the system")
+
+	// Compare command flags
+	compareCmd.Flags().StringVar(&compareLeft, "left", "", "System to compare from")
+	compareCmd.Flags().StringVar(&compareRight, "right", "", "System to compare to")
+	compareCmd.Flags().BoolVarP(&compareRecursive, "recursive", "r", false, "Include subpackages (package only)")
+	compareCmd.Flags().BoolVar(&compareShowDiff, "diff", false, "Print the diffs of differing objects (package only)")
+	compareCmd.MarkFlagRequired("left")
+	compareCmd.MarkFlagRequired("right")
+
+	// Nav command flags
+	navCmd.Flags().IntVarP(&navLine, "line", "l", 0, "Source line (1-based)")
+	navCmd.Flags().IntVarP(&navColumn, "column", "c", 0, "Source column (0-based)")
+	navCmd.Flags().StringVarP(&navFile, "file", "f", "", "Navigate in a local version of the source")
+	navCmd.MarkFlagRequired("line")
+
+	// Transport command flags
+	transportCmd.Flags().StringVarP(&transportPackage, "package", "p", "", "Package for create and check")
+	transportCmd.Flags().BoolVar(&releaseTasks, "tasks", false, "Release open tasks before the request")
+
+	// Check command flags
+	checkCmd.Flags().StringVarP(&checkFile, "file", "f", "", "Check a local source file instead of the server version")
+
+	// Fmt command flags
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "List unformatted files and exit non-zero instead of writing")
+
+	// ATC command flags
+	atcCmd.Flags().StringVar(&atcOptions.Variant, "variant", "", "ATC check variant (default: system check variant)")
+	atcCmd.Flags().StringVar(&reportFormat, "format", reports.FormatText, "Output format: text, checkstyle, sarif, junit")
+	atcCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	atcCmd.Flags().IntVar(&atcOptions.MaxVerdicts, "max-verdicts", 100, "Maximum number of findings")
+	atcCmd.Flags().IntVar(&atcOptions.FailOnPriority, "fail-on", 1, "Exit non-zero on findings with this priority or higher (0 disables)")
+
+	// Test command flags
+	testCmd.Flags().StringVar(&testFormat, "format", reports.FormatText, "Output format: text, junit")
+	testCmd.Flags().StringVarP(&testOutput, "output", "o", "", "Write the report to a file instead of stdout")
+
+	// Pull command flags
+	pullCmd.Flags().StringVarP(&pullOutDir, "out", "o", "", "Target directory (default: package name)")
+
+	// Push command flags
+	pushCmd.Flags().StringVarP(&pushPackage, "package", "p", "", "Root package of the directory (required)")
+	pushCmd.Flags().StringVarP(&pushTransport, "transport", "t", "", "Transport request for the changes")
+	pushCmd.MarkFlagRequired("package")
+
+	// MCP command flags
+	mcpCmd.Flags().StringVar(&mcpHTTPAddr, "http", "", "Serve streamable HTTP on this address (e.g. 127.0.0.1:8765) instead of stdio")
+	mcpCmd.Flags().BoolVar(&mcpAllowWrites, "allow-writes", false, "Expose tools that change the system")
+	mcpCmd.Flags().StringVar(&mcpToken, "token", os.Getenv("ABAPER_MCP_TOKEN"), "Bearer token HTTP clients must send, required for non-loopback addresses (or set ABAPER_MCP_TOKEN)")
+
+	// LSP command flags
+	lspCmd.Flags().StringVarP(&lspTransport, "transport", "t", "", "Transport request for saved changes")
+	lspCmd.Flags().BoolVar(&lspActivate, "activate", false, "Activate objects after saving")
+
 	// Add subcommands
 	rootCmd.AddCommand(serverCmd)
+	rootCmd.AddCommand(lspCmd)
+	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(putCmd)
+	rootCmd.AddCommand(createCmd)
+	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(activateCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(fmtCmd)
+	rootCmd.AddCommand(atcCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(pushCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(transportCmd)
+	rootCmd.AddCommand(whereUsedCmd)
+	rootCmd.AddCommand(navCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(connectCmd)
 
 	// Customize version template
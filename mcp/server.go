@@ -0,0 +1,295 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// Protocol versions the server can speak, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes used by the server
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// message is an incoming JSON-RPC request or notification
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is an outgoing JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+// responseError is a JSON-RPC error object
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Options controls which tools the server exposes
+type Options struct {
+	AllowWrites bool   // expose tools that change the system
+	Token       string // bearer token HTTP clients must send, none if empty
+	Version     string
+}
+
+// Server is a Model Context Protocol server exposing ADT operations as tools
+type Server struct {
+	adtClient types.ADTClient
+	logger    *zap.Logger
+	options   Options
+	tools     []tool
+}
+
+// NewServer creates an MCP server. Only read-only tools are exposed unless writes are allowed.
+func NewServer(adtClient types.ADTClient, logger *zap.Logger, options Options) *Server {
+	s := &Server{
+		adtClient: adtClient,
+		logger:    logger.With(zap.String("component", "mcp")),
+		options:   options,
+	}
+	for _, t := range s.toolset() {
+		if t.readOnly || options.AllowWrites {
+			s.tools = append(s.tools, t)
+		}
+	}
+	return s
+}
+
+// ServeStdio serves newline-delimited JSON-RPC messages until the input is closed
+func (s *Server) ServeStdio(in io.Reader, out io.Writer) error {
+	s.logger.Info("MCP server started on stdio", zap.Int("tools", len(s.tools)))
+
+	reader := bufio.NewReader(in)
+	var mu sync.Mutex
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if resp := s.handleMessage(line); resp != nil {
+				data, marshalErr := json.Marshal(resp)
+				if marshalErr != nil {
+					return marshalErr
+				}
+				mu.Lock()
+				_, writeErr := out.Write(append(data, '\n'))
+				mu.Unlock()
+				if writeErr != nil {
+					return writeErr
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			s.logger.Info("MCP server stopped")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+	}
+}
+
+// ServeHTTP implements the streamable HTTP transport. Every POST is answered with a
+// single JSON response; the server never opens an SSE stream.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browser pages may only connect from the local machine, which also defeats DNS rebinding
+	if origin := r.Header.Get("Origin"); origin != "" && !loopbackOrigin(origin) {
+		s.logger.Warn("Rejected request from foreign origin", zap.String("origin", origin))
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	if s.options.Token != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.options.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	resp := s.handleMessage(body)
+	if resp == nil {
+		// Notifications and responses are only acknowledged
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+// IsLoopback reports whether a host name or address only accepts connections from the local machine
+func IsLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// loopbackOrigin reports whether an Origin header names a page served from the local machine
+func loopbackOrigin(origin string) bool {
+	parsed, err := url.Parse(origin)
+	return err == nil && IsLoopback(parsed.Hostname())
+}
+
+// handleMessage handles a single message or a batch and returns the response(s), or nil for notifications
+func (s *Server) handleMessage(data []byte) interface{} {
+	if len(data) > 0 && data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return errorResponse(nil, codeParseError, err.Error())
+		}
+		var responses []interface{}
+		for _, item := range batch {
+			if resp := s.handleMessage(item); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return responses
+	}
+
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return errorResponse(nil, codeParseError, err.Error())
+	}
+	if msg.Method == "" {
+		// Responses to server requests are not expected
+		if len(msg.ID) > 0 {
+			return nil
+		}
+		return errorResponse(nil, codeInvalidRequest, "method required")
+	}
+
+	result, rpcErr := s.handle(&msg)
+	if len(msg.ID) == 0 {
+		return nil
+	}
+	if rpcErr != nil {
+		return &response{JSONRPC: "2.0", ID: msg.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: msg.ID, Result: result}
+}
+
+// handle dispatches a request or notification
+func (s *Server) handle(msg *message) (interface{}, *responseError) {
+	s.logger.Debug("Handling message", zap.String("method", msg.Method))
+
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if len(msg.Params) > 0 {
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+			}
+		}
+		return s.initialize(params.ProtocolVersion), nil
+
+	case "ping":
+		return struct{}{}, nil
+
+	case "tools/list":
+		return map[string]interface{}{"tools": s.toolDescriptions()}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.callTool(params.Name, params.Arguments)
+
+	default:
+		// Notifications like notifications/initialized need no handling
+		if len(msg.ID) == 0 {
+			return nil, nil
+		}
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+	}
+}
+
+// initialize negotiates the protocol version and announces the tool capability
+func (s *Server) initialize(requested string) interface{} {
+	version := protocolVersions[0]
+	for _, supported := range protocolVersions {
+		if supported == requested {
+			version = requested
+			break
+		}
+	}
+
+	s.logger.Info("MCP session initialized",
+		zap.String("protocol_version", version),
+		zap.Bool("allow_writes", s.options.AllowWrites))
+
+	instructions := "Read-only access to an SAP ABAP system via ADT."
+	if s.options.AllowWrites {
+		instructions = "Access to an SAP ABAP system via ADT. Write tools change the system, use them only when asked to."
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]bool{"listChanged": false},
+		},
+		"serverInfo": map[string]string{
+			"name":    "abaper",
+			"version": s.options.Version,
+		},
+		"instructions": instructions,
+	}
+}
+
+// errorResponse builds an error response; a missing ID is sent as null
+func errorResponse(id json.RawMessage, code int, text string) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: text}}
+}
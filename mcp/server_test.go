@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const pingRequest = `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+func TestServeHTTPGating(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		method        string
+		origin        string
+		authorization string
+		wantStatus    int
+	}{
+		{"no origin without token", "", http.MethodPost, "", "", http.StatusOK},
+		{"localhost origin", "", http.MethodPost, "http://localhost:3000", "", http.StatusOK},
+		{"ipv4 loopback origin", "", http.MethodPost, "http://127.0.0.1:8080", "", http.StatusOK},
+		{"ipv6 loopback origin", "", http.MethodPost, "http://[::1]:8080", "", http.StatusOK},
+		{"foreign origin", "", http.MethodPost, "https://evil.example.com", "", http.StatusForbidden},
+		{"rebound host name", "", http.MethodPost, "http://localhost.evil.example.com", "", http.StatusForbidden},
+		{"foreign origin with token", "s3cret", http.MethodPost, "https://evil.example.com", "Bearer s3cret", http.StatusForbidden},
+		{"token sent", "s3cret", http.MethodPost, "", "Bearer s3cret", http.StatusOK},
+		{"token missing", "s3cret", http.MethodPost, "", "", http.StatusUnauthorized},
+		{"wrong token", "s3cret", http.MethodPost, "", "Bearer guess", http.StatusUnauthorized},
+		{"token without scheme", "s3cret", http.MethodPost, "", "s3cret", http.StatusOK},
+		{"get not allowed", "", http.MethodGet, "", "", http.StatusMethodNotAllowed},
+		{"get without token", "s3cret", http.MethodGet, "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(nil, zap.NewNop(), Options{Token: tt.token})
+
+			req := httptest.NewRequest(tt.method, "/mcp", strings.NewReader(pingRequest))
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+			if tt.wantStatus == http.StatusOK {
+				var resp struct {
+					ID    int             `json:"id"`
+					Error json.RawMessage `json:"error"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("invalid response %s: %v", rec.Body.String(), err)
+				}
+				if resp.ID != 1 || resp.Error != nil {
+					t.Errorf("response = %s, want a result for id 1", rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestServeHTTPNotification(t *testing.T) {
+	s := NewServer(nil, zap.NewNop(), Options{})
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"LOCALHOST", true},
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"::1", true},
+		{"[::1]", true},
+		{"", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"192.168.1.10", false},
+		{"sapdev.example.com", false},
+		{"localhost.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := IsLoopback(tt.host); got != tt.want {
+				t.Errorf("IsLoopback(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+	"go.uber.org/zap"
+)
+
+// tool is an ADT operation exposed to MCP clients
+type tool struct {
+	name        string
+	description string
+	schema      map[string]interface{}
+	readOnly    bool
+	call        func(args json.RawMessage) (string, error)
+}
+
+// objectArgs identifies a repository object in tool arguments
+type objectArgs struct {
+	ObjectType    string `json:"object_type"`
+	ObjectName    string `json:"object_name"`
+	FunctionGroup string `json:"function_group"`
+	Include       string `json:"include"`
+}
+
+// objectTypes are the object types accepted by object tools
+var objectTypes = []string{"program", "include", "class", "interface", "function", "function_group", "table", "structure"}
+
+// objectSchema returns the input schema of a tool working on a single object plus extra properties
+func objectSchema(extra map[string]interface{}, required ...string) map[string]interface{} {
+	properties := map[string]interface{}{
+		"object_type": map[string]interface{}{
+			"type":        "string",
+			"enum":        objectTypes,
+			"description": "Object type",
+		},
+		"object_name": map[string]interface{}{
+			"type":        "string",
+			"description": "Object name, e.g. ZCL_SALES_HELPER",
+		},
+		"function_group": map[string]interface{}{
+			"type":        "string",
+			"description": "Function group (function modules only)",
+		},
+	}
+	for name, property := range extra {
+		properties[name] = property
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   append([]string{"object_type", "object_name"}, required...),
+	}
+}
+
+// includeProperty selects a class include
+var includeProperty = map[string]interface{}{
+	"type":        "string",
+	"enum":        types.ClassIncludes,
+	"description": "Class include (classes only, default main)",
+}
+
+// sourceURI resolves the source URI of the object in the arguments
+func (a objectArgs) sourceURI() (types.ADTObject, string, error) {
+	obj, err := types.ObjectReference(a.ObjectType, a.ObjectName, a.FunctionGroup)
+	if err != nil {
+		return obj, "", err
+	}
+	if obj.Type == types.ObjectTypePackage {
+		return obj, "", fmt.Errorf("packages have no source, use package_contents")
+	}
+	if a.Include != "" {
+		if obj.Type != types.ObjectTypeClass {
+			return obj, "", fmt.Errorf("include is only supported for classes")
+		}
+		uri, err := types.ClassIncludeURI(obj.Name, a.Include)
+		return obj, uri, err
+	}
+	return obj, obj.URI + "/source/main", nil
+}
+
+// toolset returns all tools; write tools are filtered out unless allowed
+func (s *Server) toolset() []tool {
+	return []tool{
+		{
+			name:        "get_source",
+			description: "Get the ABAP source code of a program, class (or class include), interface, function module, include, table or structure.",
+			schema:      objectSchema(map[string]interface{}{"include": includeProperty}),
+			readOnly:    true,
+			call:        s.getSource,
+		},
+		{
+			name:        "search_objects",
+			description: "Search repository objects by name pattern (* wildcards), optionally restricted to object types.",
+			schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"pattern": map[string]interface{}{
+						"type":        "string",
+						"description": "Name pattern, e.g. ZCL_SALES*",
+					},
+					"object_types": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Object types such as program or class, or ADT codes like PROG/P",
+					},
+					"max_results": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of results (default 100)",
+					},
+				},
+				"required": []string{"pattern"},
+			},
+			readOnly: true,
+			call:     s.searchObjects,
+		},
+		{
+			name:        "package_contents",
+			description: "List the objects and subpackages of a package.",
+			schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"package_name": map[string]interface{}{
+						"type":        "string",
+						"description": "Package name, e.g. ZSALES or $TMP",
+					},
+				},
+				"required": []string{"package_name"},
+			},
+			readOnly: true,
+			call:     s.packageContents,
+		},
+		{
+			name:        "where_used",
+			description: "List the objects that use an object, with the source lines of each usage.",
+			schema:      objectSchema(nil),
+			readOnly:    true,
+			call:        s.whereUsed,
+		},
+		{
+			name:        "syntax_check",
+			description: "Run the ABAP syntax check on an object, or on a modified source without saving it.",
+			schema: objectSchema(map[string]interface{}{
+				"include": includeProperty,
+				"source": map[string]interface{}{
+					"type":        "string",
+					"description": "Source to check instead of the version on the system",
+				},
+			}),
+			readOnly: true,
+			call:     s.syntaxCheck,
+		},
+		{
+			name:        "table_contents",
+			description: "Read rows of a database table.",
+			schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"table_name": map[string]interface{}{
+						"type":        "string",
+						"description": "Table name, e.g. T000",
+					},
+					"max_rows": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of rows (default 100)",
+					},
+				},
+				"required": []string{"table_name"},
+			},
+			readOnly: true,
+			call:     s.tableContents,
+		},
+		{
+			name:        "update_source",
+			description: "Write the source of an object (left inactive until activated).",
+			schema: objectSchema(map[string]interface{}{
+				"include": includeProperty,
+				"source": map[string]interface{}{
+					"type":        "string",
+					"description": "Complete new source",
+				},
+				"transport": map[string]interface{}{
+					"type":        "string",
+					"description": "Transport request for the change",
+				},
+			}, "source"),
+			call: s.updateSource,
+		},
+		{
+			name:        "activate",
+			description: "Activate an object.",
+			schema:      objectSchema(nil),
+			call:        s.activate,
+		},
+	}
+}
+
+// toolDescriptions returns the tools/list entries
+func (s *Server) toolDescriptions() []map[string]interface{} {
+	descriptions := []map[string]interface{}{}
+	for _, t := range s.tools {
+		descriptions = append(descriptions, map[string]interface{}{
+			"name":        t.name,
+			"description": t.description,
+			"inputSchema": t.schema,
+			"annotations": map[string]bool{
+				"readOnlyHint":    t.readOnly,
+				"destructiveHint": !t.readOnly,
+			},
+		})
+	}
+	return descriptions
+}
+
+// callTool runs a tool. Tool failures are reported in the result so the model can see them.
+func (s *Server) callTool(name string, args json.RawMessage) (interface{}, *responseError) {
+	var selected *tool
+	for i := range s.tools {
+		if s.tools[i].name == name {
+			selected = &s.tools[i]
+			break
+		}
+	}
+	if selected == nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+	}
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	s.logger.Info("Calling tool", zap.String("tool", name))
+
+	text, err := selected.call(args)
+	if err != nil {
+		s.logger.Warn("Tool failed", zap.String("tool", name), zap.Error(err))
+		return toolResult(err.Error(), true), nil
+	}
+	return toolResult(text, false), nil
+}
+
+// toolResult wraps text in a tools/call result
+func toolResult(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+// jsonText renders a tool result as indented JSON
+func jsonText(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *Server) getSource(raw json.RawMessage) (string, error) {
+	var args objectArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	_, uri, err := args.sourceURI()
+	if err != nil {
+		return "", err
+	}
+	source, err := s.adtClient.GetSourceByURI(uri)
+	if err != nil {
+		return "", err
+	}
+	return source.Source, nil
+}
+
+func (s *Server) searchObjects(raw json.RawMessage) (string, error) {
+	var args struct {
+		Pattern     string   `json:"pattern"`
+		ObjectTypes []string `json:"object_types"`
+		MaxResults  int      `json:"max_results"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.Pattern) == "" {
+		return "", fmt.Errorf("pattern required")
+	}
+	result, err := s.adtClient.SearchObjectsWithOptions(args.Pattern, types.SearchOptions{
+		ObjectTypes: args.ObjectTypes,
+		MaxResults:  args.MaxResults,
+	})
+	if err != nil {
+		return "", err
+	}
+	return jsonText(map[string]interface{}{
+		"objects":  result.Objects,
+		"has_more": result.HasMore,
+	})
+}
+
+func (s *Server) packageContents(raw json.RawMessage) (string, error) {
+	var args struct {
+		PackageName string `json:"package_name"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.PackageName) == "" {
+		return "", fmt.Errorf("package_name required")
+	}
+	pkg, err := s.adtClient.GetPackageContents(strings.ToUpper(args.PackageName))
+	if err != nil {
+		return "", err
+	}
+	return jsonText(pkg)
+}
+
+func (s *Server) whereUsed(raw json.RawMessage) (string, error) {
+	var args objectArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	obj, err := types.ObjectReference(args.ObjectType, args.ObjectName, args.FunctionGroup)
+	if err != nil {
+		return "", err
+	}
+	usages, err := s.adtClient.WhereUsed(obj.URI)
+	if err != nil {
+		return "", err
+	}
+	return jsonText(usages)
+}
+
+func (s *Server) syntaxCheck(raw json.RawMessage) (string, error) {
+	var args struct {
+		objectArgs
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	obj, uri, err := args.sourceURI()
+	if err != nil {
+		return "", err
+	}
+	if args.Include == "" {
+		uri = obj.URI
+	}
+	result, err := s.adtClient.SyntaxCheck(uri, args.Source)
+	if err != nil {
+		return "", err
+	}
+	return jsonText(result)
+}
+
+func (s *Server) tableContents(raw json.RawMessage) (string, error) {
+	var args struct {
+		TableName string `json:"table_name"`
+		MaxRows   int    `json:"max_rows"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.TableName) == "" {
+		return "", fmt.Errorf("table_name required")
+	}
+	data, err := s.adtClient.GetTableContents(args.TableName, args.MaxRows)
+	if err != nil {
+		return "", err
+	}
+	return jsonText(data)
+}
+
+func (s *Server) updateSource(raw json.RawMessage) (string, error) {
+	var args struct {
+		objectArgs
+		Source    string `json:"source"`
+		Transport string `json:"transport"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	obj, uri, err := args.sourceURI()
+	if err != nil {
+		return "", err
+	}
+	if args.Source == "" {
+		return "", fmt.Errorf("source required")
+	}
+
+	current, err := s.adtClient.GetSourceByURI(uri)
+	if err != nil {
+		return "", err
+	}
+	current.Source = args.Source
+	if _, err := s.adtClient.UpdateSource(current, args.Transport); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Source of %s %s updated (inactive)", obj.Type, obj.Name), nil
+}
+
+func (s *Server) activate(raw json.RawMessage) (string, error) {
+	var args objectArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	obj, err := types.ObjectReference(args.ObjectType, args.ObjectName, args.FunctionGroup)
+	if err != nil {
+		return "", err
+	}
+	result, err := s.adtClient.Activate([]types.ADTObject{obj})
+	if err != nil {
+		return "", err
+	}
+	return jsonText(result)
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bluefunda/abaper/credentials"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// SystemProfile holds the connection settings of a named SAP system
+type SystemProfile struct {
+	Host           string             `yaml:"host"`
+	Client         string             `yaml:"client,omitempty"`
+	Language       string             `yaml:"language,omitempty"`
+	User           string             `yaml:"user,omitempty"`
+	TLS            ProfileTLS         `yaml:"tls,omitempty"`
+	ConnectTimeout int                `yaml:"connect_timeout,omitempty"` // seconds
+	RequestTimeout int                `yaml:"request_timeout,omitempty"` // seconds
+	SessionTimeout int                `yaml:"session_timeout,omitempty"` // minutes an unused login session is reused
+	Package        string             `yaml:"package,omitempty"`         // default package for new objects
+	Transport      string             `yaml:"transport,omitempty"`       // default transport request for changes
+	Credentials    ProfileCredentials `yaml:"credentials,omitempty"`
+}
+
+// ProfileCredentials selects where the password of a system is read from
+type ProfileCredentials struct {
+	Backend      string `yaml:"backend,omitempty"`       // auto, secret-service, file, pass or helper
+	Helper       string `yaml:"helper,omitempty"`        // credential helper command (helper backend)
+	PasswordFile string `yaml:"password_file,omitempty"` // pass-style file, the first line is the password
+}
+
+// ProfileTLS holds the TLS settings of a system
+type ProfileTLS struct {
+	Insecure *bool  `yaml:"insecure,omitempty"` // skip certificate verification (default false)
+	CAFile   string `yaml:"ca_file,omitempty"`  // PEM file with additional trusted certificates, implies verification
+}
+
+// ProfileFile is the abaper configuration file
+type ProfileFile struct {
+	Current string                    `yaml:"current,omitempty"`
+	Systems map[string]*SystemProfile `yaml:"systems,omitempty"`
+}
+
+// defaultConfigPath returns ~/.config/abaper/config.yaml, honouring XDG_CONFIG_HOME
+func defaultConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "abaper", "config.yaml"), nil
+}
+
+// configPath returns the configuration file selected with --config or the default path
+func configPath(config *Config) (string, error) {
+	if config.ConfigFile != "" {
+		return config.ConfigFile, nil
+	}
+	return defaultConfigPath()
+}
+
+// loadProfiles reads the configuration file. A missing default file yields an empty
+// configuration; a missing file given with --config is an error.
+func loadProfiles(config *Config) (*ProfileFile, string, error) {
+	path, err := configPath(config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	profiles := &ProfileFile{Systems: make(map[string]*SystemProfile)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && config.ConfigFile == "" {
+		return profiles, path, nil
+	}
+	if err != nil {
+		return nil, path, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, profiles); err != nil {
+		return nil, path, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if profiles.Systems == nil {
+		profiles.Systems = make(map[string]*SystemProfile)
+	}
+	return profiles, path, nil
+}
+
+// save writes the configuration file, readable by the owner only
+func (p *ProfileFile) save(path string) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// names returns the system names in alphabetical order
+func (p *ProfileFile) names() []string {
+	names := make([]string, 0, len(p.Systems))
+	for name := range p.Systems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup finds a system by name, ignoring case
+func (p *ProfileFile) lookup(name string) (string, *SystemProfile, bool) {
+	if profile, ok := p.Systems[name]; ok {
+		return name, profile, true
+	}
+	for candidate, profile := range p.Systems {
+		if strings.EqualFold(candidate, name) {
+			return candidate, profile, true
+		}
+	}
+	return "", nil, false
+}
+
+// validate checks the settings of a profile before it is saved
+func (s *SystemProfile) validate() error {
+	if strings.TrimSpace(s.Host) == "" {
+		return fmt.Errorf("host required")
+	}
+	if s.ConnectTimeout < 0 || s.RequestTimeout < 0 || s.SessionTimeout < 0 {
+		return fmt.Errorf("timeouts must not be negative")
+	}
+	if s.Credentials.Backend != "" && !slices.Contains(credentials.Backends, s.Credentials.Backend) {
+		return fmt.Errorf("unknown credential backend: %s (use %s)", s.Credentials.Backend, strings.Join(credentials.Backends, ", "))
+	}
+	if s.Credentials.Backend == credentials.BackendHelper && s.Credentials.Helper == "" {
+		return fmt.Errorf("credential helper command required for the helper backend")
+	}
+	if s.TLS.CAFile != "" && s.TLS.Insecure != nil && *s.TLS.Insecure {
+		return fmt.Errorf("a CA file is only used when certificates are verified, remove insecure or ca_file")
+	}
+	if s.TLS.CAFile != "" {
+		data, err := os.ReadFile(s.TLS.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA file: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return fmt.Errorf("CA file %s contains no PEM certificates", s.TLS.CAFile)
+		}
+	}
+	return nil
+}
+
+// applyTo copies the connection settings of the profile into a configuration. Client and
+// user fall back to the configuration's own; settings in keep are left alone, e.g.
+// because they were given as flags.
+func (s *SystemProfile) applyTo(config *Config, keep map[string]bool) {
+	if !keep["host"] {
+		config.ADTHost = s.Host
+	}
+	if !keep["client"] && s.Client != "" {
+		config.ADTClient = s.Client
+	}
+	if !keep["user"] && s.User != "" {
+		config.ADTUsername = s.User
+	}
+	config.Language = s.Language
+	config.AllowSelfSigned = s.TLS.Insecure != nil && *s.TLS.Insecure && s.TLS.CAFile == ""
+	config.CAFile = s.TLS.CAFile
+	config.ConnectTimeout = s.ConnectTimeout
+	config.RequestTimeout = s.RequestTimeout
+	config.SessionTimeout = s.SessionTimeout
+	config.Credentials = s.Credentials.Backend
+	if config.Credentials == "" {
+		config.Credentials = os.Getenv("ABAPER_CREDENTIALS")
+	}
+	config.CredentialHelper = s.Credentials.Helper
+	config.PasswordFile = s.Credentials.PasswordFile
+}
+
+// HandleConfig manages the system profiles in the configuration file
+func HandleConfig(cmd *cobra.Command, args []string, quiet bool, normal bool) error {
+	profiles, path, err := loadProfiles(rootConfig)
+	if err != nil {
+		return err
+	}
+
+	action, name := strings.ToLower(args[0]), ""
+	if len(args) > 1 {
+		name = args[1]
+	}
+	if action != "list" && name == "" {
+		return fmt.Errorf("system name required: %s config %s NAME", PROGRAM_NAME, action)
+	}
+
+	switch action {
+	case "list":
+		return HandleConfigList(profiles, path)
+
+	case "add":
+		existing, profile, ok := profiles.lookup(name)
+		if ok {
+			name = existing
+		} else {
+			profile = &SystemProfile{}
+		}
+		applyProfileFlags(cmd, profile)
+		if err := profile.validate(); err != nil {
+			return fmt.Errorf("invalid system %s: %w", name, err)
+		}
+
+		profiles.Systems[name] = profile
+		if profiles.Current == "" {
+			profiles.Current = name
+		}
+		if err := profiles.save(path); err != nil {
+			return err
+		}
+
+		verb := "Added"
+		if ok {
+			verb = "Updated"
+		}
+		fmt.Printf("✅ %s system %s (%s)\n", verb, name, profile.Host)
+		if profiles.Current == name && !ok && (!quiet || normal) {
+			fmt.Printf("   %s is now the current system\n", name)
+		}
+		return nil
+
+	case "remove":
+		existing, _, ok := profiles.lookup(name)
+		if !ok {
+			return fmt.Errorf("system %s not found in %s", name, path)
+		}
+		delete(profiles.Systems, existing)
+		if profiles.Current == existing {
+			profiles.Current = ""
+		}
+		if err := profiles.save(path); err != nil {
+			return err
+		}
+		fmt.Printf("🗑️  Removed system %s\n", existing)
+		return nil
+
+	case "use":
+		existing, _, ok := profiles.lookup(name)
+		if !ok {
+			return fmt.Errorf("system %s not found in %s", name, path)
+		}
+		profiles.Current = existing
+		if err := profiles.save(path); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Current system is now %s\n", existing)
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported config action: %s (use list, add, remove or use)", action)
+	}
+}
+
+// HandleConfigList prints the configured systems; the current one is marked with *
+func HandleConfigList(profiles *ProfileFile, path string) error {
+	if len(profiles.Systems) == 0 {
+		fmt.Printf("No systems configured in %s\n", path)
+		fmt.Printf("Add one with: %s config add NAME --host HOST [--client CLIENT] [--user USER]\n", PROGRAM_NAME)
+		return nil
+	}
+
+	fmt.Printf("\n=== Systems (%s) ===\n", path)
+	for _, name := range profiles.names() {
+		profile := profiles.Systems[name]
+		marker := " "
+		if name == profiles.Current {
+			marker = "*"
+		}
+		fmt.Printf("%s %-12s %s", marker, name, profile.Host)
+		if profile.Client != "" {
+			fmt.Printf(" client %s", profile.Client)
+		}
+		if profile.User != "" {
+			fmt.Printf(" user %s", profile.User)
+		}
+		if profile.Package != "" {
+			fmt.Printf(" package %s", profile.Package)
+		}
+		if profile.Transport != "" {
+			fmt.Printf(" transport %s", profile.Transport)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// applyProfileFlags copies the settings given as flags into a profile, so that
+// adding an existing system only changes what was given
+func applyProfileFlags(cmd *cobra.Command, profile *SystemProfile) {
+	flags := cmd.Flags()
+	if flags.Changed("host") {
+		profile.Host = configHost
+	}
+	if flags.Changed("client") {
+		profile.Client = configClient
+	}
+	if flags.Changed("language") {
+		profile.Language = strings.ToUpper(configLanguage)
+	}
+	if flags.Changed("user") {
+		profile.User = configUser
+	}
+	if flags.Changed("insecure") {
+		insecure := configInsecure
+		profile.TLS.Insecure = &insecure
+	}
+	if flags.Changed("ca-file") {
+		// A CA file is given to verify certificates against it
+		if !flags.Changed("insecure") && configCAFile != "" {
+			profile.TLS.Insecure = nil
+		}
+		profile.TLS.CAFile = configCAFile
+		if abs, err := filepath.Abs(configCAFile); err == nil && configCAFile != "" {
+			profile.TLS.CAFile = abs
+		}
+	}
+	if flags.Changed("connect-timeout") {
+		profile.ConnectTimeout = configConnectTimeout
+	}
+	if flags.Changed("request-timeout") {
+		profile.RequestTimeout = configRequestTimeout
+	}
+	if flags.Changed("session-timeout") {
+		profile.SessionTimeout = configSessionTimeout
+	}
+	if flags.Changed("credentials") {
+		profile.Credentials.Backend = strings.ToLower(configCredentials)
+	}
+	if flags.Changed("credential-helper") {
+		profile.Credentials.Helper = configHelper
+	}
+	if flags.Changed("password-file") {
+		profile.Credentials.PasswordFile = configPasswordFile
+		if abs, err := filepath.Abs(configPasswordFile); err == nil && configPasswordFile != "" {
+			profile.Credentials.PasswordFile = abs
+		}
+	}
+	if flags.Changed("package") {
+		profile.Package = strings.ToUpper(configPackage)
+	}
+	if flags.Changed("transport") {
+		profile.Transport = strings.ToUpper(configTransport)
+	}
+}
@@ -0,0 +1,393 @@
+// Package reports renders check and test results in machine-readable formats
+package reports
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bluefunda/abaper/types"
+)
+
+// Report formats for machine-readable output
+const (
+	FormatText       = "text"
+	FormatCheckstyle = "checkstyle"
+	FormatSARIF      = "sarif"
+	FormatJUnit      = "junit"
+)
+
+// ToolVersion is reported as the tool version in SARIF output
+var ToolVersion = "dev"
+
+// Checkstyle report structures
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// SARIF 2.1.0 report structures (subset)
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// JUnit report structures
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr,omitempty"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr,omitempty"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// atcSeverity maps an ATC priority to a checkstyle/SARIF severity
+func atcSeverity(priority int) string {
+	switch priority {
+	case 1:
+		return "error"
+	case 2:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// writeXML writes an XML document with header and indentation
+func writeXML(w io.Writer, report interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteATC writes ATC findings in the requested machine-readable format
+func WriteATC(w io.Writer, result *types.ADTATCResult, format string) error {
+	switch format {
+	case FormatCheckstyle:
+		return writeXML(w, atcCheckstyleReport(result))
+	case FormatSARIF:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(atcSARIFReport(result))
+	case FormatJUnit:
+		return writeXML(w, atcJUnitReport(result))
+	default:
+		return fmt.Errorf("unsupported report format: %s (use checkstyle, sarif or junit)", format)
+	}
+}
+
+// ATCFindingsByObject groups findings by object name in a stable order
+func ATCFindingsByObject(result *types.ADTATCResult) ([]string, map[string][]types.ADTATCFinding) {
+	grouped := make(map[string][]types.ADTATCFinding)
+	var names []string
+	for _, finding := range result.Findings {
+		if _, ok := grouped[finding.ObjectName]; !ok {
+			names = append(names, finding.ObjectName)
+		}
+		grouped[finding.ObjectName] = append(grouped[finding.ObjectName], finding)
+	}
+	sort.Strings(names)
+	return names, grouped
+}
+
+// atcFindingLocation returns the file name used for a finding in reports
+func atcFindingLocation(finding types.ADTATCFinding) string {
+	if finding.URI != "" {
+		return finding.URI
+	}
+	return finding.ObjectURI
+}
+
+func atcCheckstyleReport(result *types.ADTATCResult) *checkstyleReport {
+	report := &checkstyleReport{Version: "4.3"}
+	files := make(map[string]int)
+
+	for _, finding := range result.Findings {
+		name := atcFindingLocation(finding)
+		idx, ok := files[name]
+		if !ok {
+			report.Files = append(report.Files, checkstyleFile{Name: name})
+			idx = len(report.Files) - 1
+			files[name] = idx
+		}
+		report.Files[idx].Errors = append(report.Files[idx].Errors, checkstyleError{
+			Line:     finding.Line,
+			Column:   finding.Column,
+			Severity: atcSeverity(finding.Priority),
+			Message:  finding.MessageTitle,
+			Source:   "atc." + finding.CheckID + "." + finding.MessageID,
+		})
+	}
+
+	return report
+}
+
+func atcSARIFReport(result *types.ADTATCResult) *sarifReport {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "abaper-atc",
+			InformationURI: "https://github.com/bluefunda/abaper",
+			Version:        ToolVersion,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := make(map[string]bool)
+	for _, finding := range result.Findings {
+		ruleID := finding.CheckID + "/" + finding.MessageID
+		if !rules[ruleID] {
+			rules[ruleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               ruleID,
+				ShortDescription: sarifMessage{Text: finding.CheckTitle},
+			})
+		}
+
+		level := atcSeverity(finding.Priority)
+		if level == "info" {
+			level = "note"
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: atcFindingLocation(finding)}}
+		if finding.Line > 0 {
+			location.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    ruleID,
+			Level:     level,
+			Message:   sarifMessage{Text: finding.MessageTitle},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	return &sarifReport{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+func atcJUnitReport(result *types.ADTATCResult) *junitTestSuites {
+	report := &junitTestSuites{Name: "ATC " + result.Variant}
+	names, grouped := ATCFindingsByObject(result)
+
+	for _, name := range names {
+		suite := junitTestSuite{Name: name}
+		for _, finding := range grouped[name] {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      fmt.Sprintf("%s: %s", finding.CheckTitle, finding.MessageTitle),
+				ClassName: name,
+				Failure: &junitFailure{
+					Message: finding.MessageTitle,
+					Type:    atcSeverity(finding.Priority),
+					Content: fmt.Sprintf("%s:%d:%d", atcFindingLocation(finding), finding.Line, finding.Column),
+				},
+			})
+			suite.Tests++
+			suite.Failures++
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	return report
+}
+
+// WriteUnitJUnit writes an ABAP Unit run result as JUnit XML
+func WriteUnitJUnit(w io.Writer, result *types.ADTUnitRunResult) error {
+	return writeXML(w, unitJUnitReport(result))
+}
+
+func unitJUnitReport(result *types.ADTUnitRunResult) *junitTestSuites {
+	report := &junitTestSuites{Name: "ABAP Unit"}
+	var total float64
+
+	for _, program := range result.Programs {
+		for _, class := range program.Classes {
+			suite := junitTestSuite{Name: program.Name + "." + class.Name}
+			var suiteTime float64
+
+			for _, method := range class.Methods {
+				testCase := junitTestCase{
+					Name:      method.Name,
+					ClassName: suite.Name,
+					Time:      fmt.Sprintf("%.3f", method.ExecutionTime),
+				}
+				switch outcome, alert := types.UnitOutcome(method.Alerts); outcome {
+				case types.UnitFailed:
+					testCase.Failure = unitFailure(alert)
+				case types.UnitError:
+					testCase.Error = unitFailure(alert)
+				}
+				suiteTime += method.ExecutionTime
+				suite.addCase(testCase)
+			}
+
+			// Class level alerts (e.g. failing setup) are reported as an error case
+			if outcome, alert := types.UnitOutcome(class.Alerts); outcome != types.UnitPassed {
+				suite.addCase(junitTestCase{Name: "[class setup]", ClassName: suite.Name, Error: unitFailure(alert)})
+			}
+
+			suite.Time = fmt.Sprintf("%.3f", suiteTime)
+			total += suiteTime
+			report.addSuite(suite)
+		}
+
+		// Program level alerts (e.g. the program could not be loaded) get a suite of their own
+		if outcome, alert := types.UnitOutcome(program.Alerts); outcome != types.UnitPassed {
+			suite := junitTestSuite{Name: program.Name, Time: "0.000"}
+			suite.addCase(junitTestCase{Name: "[program]", ClassName: program.Name, Error: unitFailure(alert)})
+			report.addSuite(suite)
+		}
+	}
+
+	report.Time = fmt.Sprintf("%.3f", total)
+	return report
+}
+
+// addCase appends a test case and counts it
+func (s *junitTestSuite) addCase(testCase junitTestCase) {
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	if testCase.Error != nil {
+		s.Errors++
+	}
+	s.Cases = append(s.Cases, testCase)
+}
+
+// addSuite appends a suite and adds its counts to the totals
+func (r *junitTestSuites) addSuite(suite junitTestSuite) {
+	r.Tests += suite.Tests
+	r.Failures += suite.Failures
+	r.Errors += suite.Errors
+	r.Suites = append(r.Suites, suite)
+}
+
+// unitFailure converts the alert deciding a test outcome
+func unitFailure(alert *types.ADTUnitAlert) *junitFailure {
+	return &junitFailure{
+		Message: alert.Title,
+		Type:    alert.Kind,
+		Content: unitAlertText(*alert),
+	}
+}
+
+// unitAlertText renders alert details and stack as plain text
+func unitAlertText(alert types.ADTUnitAlert) string {
+	var b strings.Builder
+	for _, detail := range alert.Details {
+		b.WriteString(detail)
+		b.WriteString("\n")
+	}
+	for _, entry := range alert.Stack {
+		fmt.Fprintf(&b, "    at %s %s\n", entry.Name, entry.Description)
+	}
+	return b.String()
+}
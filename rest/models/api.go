@@ -27,6 +27,75 @@ type ObjectRequest struct {
 	Args       []string `json:"args,omitempty"` // For function groups, etc.
 }
 
+// CheckRequest for syntax check requests
+type CheckRequest struct {
+	ObjectType string   `json:"object_type"`
+	ObjectName string   `json:"object_name"`
+	Args       []string `json:"args,omitempty"`   // Function group for function modules
+	Source     string   `json:"source,omitempty"` // Local source to check instead of the server version
+}
+
+// NavigationRequest for go-to-definition requests. The object is given either
+// by object_uri or by object_type/object_name.
+type NavigationRequest struct {
+	ObjectURI  string   `json:"object_uri,omitempty"`
+	ObjectType string   `json:"object_type,omitempty"`
+	ObjectName string   `json:"object_name,omitempty"`
+	Args       []string `json:"args,omitempty"`   // Function group for function modules
+	Source     string   `json:"source,omitempty"` // Local source instead of the server version
+	Line       int      `json:"line"`             // 1-based
+	Column     int      `json:"column"`           // 0-based
+}
+
+// CompletionRequest for code completion requests. The object gives the context the
+// source belongs to; if identifier is set only that proposal is returned with its insert_text.
+type CompletionRequest struct {
+	ObjectURI  string   `json:"object_uri,omitempty"`
+	ObjectType string   `json:"object_type,omitempty"`
+	ObjectName string   `json:"object_name,omitempty"`
+	Args       []string `json:"args,omitempty"` // Function group for function modules
+	Source     string   `json:"source"`         // Current editor buffer
+	Line       int      `json:"line"`           // 1-based
+	Column     int      `json:"column"`         // 0-based cursor position
+	Identifier string   `json:"identifier,omitempty"`
+}
+
+// TestRunRequest for ABAP Unit test run requests
+type TestRunRequest struct {
+	ObjectType string   `json:"object_type"`
+	ObjectName string   `json:"object_name"`
+	Args       []string `json:"args,omitempty"`   // Function group for function modules
+	Format     string   `json:"format,omitempty"` // "json" (default) or "junit"
+}
+
+// TransportCreateRequest for transport request creation
+type TransportCreateRequest struct {
+	Description string `json:"description"`
+	Package     string `json:"package"`
+	ObjectURI   string `json:"object_uri,omitempty"` // Object the request is created for
+}
+
+// TransportCheckRequest for checking which transport an object change needs
+type TransportCheckRequest struct {
+	ObjectType string   `json:"object_type"`
+	ObjectName string   `json:"object_name"`
+	Args       []string `json:"args,omitempty"` // Function group for function modules
+	Package    string   `json:"package,omitempty"`
+}
+
+// TransportAssignRequest for recording an object in a transport request or task
+type TransportAssignRequest struct {
+	RequestID  string   `json:"request_id"`
+	ObjectType string   `json:"object_type"`
+	ObjectName string   `json:"object_name"`
+	Args       []string `json:"args,omitempty"` // Function group for function modules
+}
+
+// TransportReleaseRequest for releasing a transport request or task
+type TransportReleaseRequest struct {
+	RequestID string `json:"request_id"`
+}
+
 // SearchRequest for object search requests
 type SearchRequest struct {
 	Pattern     string   `json:"pattern"`
@@ -2,10 +2,14 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bluefunda/abaper/reports"
 	"github.com/bluefunda/abaper/rest/models"
 	"github.com/bluefunda/abaper/types"
 	"go.uber.org/zap"
@@ -45,7 +49,16 @@ func (rs *RestServer) Start(port string) {
 	// API endpoints for CLI parity (no AI)
 	http.HandleFunc("/api/v1/objects/get", rs.corsHandler(rs.getObjectHandler))
 	http.HandleFunc("/api/v1/objects/search", rs.corsHandler(rs.searchObjectsHandler))
+	http.HandleFunc("/api/v1/objects/check", rs.corsHandler(rs.checkObjectHandler))
 	http.HandleFunc("/api/v1/objects/list", rs.corsHandler(rs.listObjectsHandler))
+	http.HandleFunc("/api/v1/tests/run", rs.corsHandler(rs.runTestsHandler))
+	http.HandleFunc("/api/v1/navigation/definition", rs.corsHandler(rs.navigationDefinitionHandler))
+	http.HandleFunc("/api/v1/completion", rs.corsHandler(rs.completionHandler))
+	http.HandleFunc("/api/v1/transports/list", rs.corsHandler(rs.listTransportsHandler))
+	http.HandleFunc("/api/v1/transports/create", rs.sameOriginHandler(rs.createTransportHandler))
+	http.HandleFunc("/api/v1/transports/check", rs.corsHandler(rs.checkTransportHandler))
+	http.HandleFunc("/api/v1/transports/assign", rs.sameOriginHandler(rs.assignTransportHandler))
+	http.HandleFunc("/api/v1/transports/release", rs.sameOriginHandler(rs.releaseTransportHandler))
 	http.HandleFunc("/api/v1/system/connect", rs.corsHandler(rs.connectHandler))
 
 	// Removed AI endpoints - return feature removed messages
@@ -62,7 +75,7 @@ func (rs *RestServer) Start(port string) {
 	http.HandleFunc("/health", rs.healthHandler)
 	http.HandleFunc("/version", rs.versionHandler)
 
-	rs.logger.Info("REST server endpoints registered (CLI parity + removed AI endpoints)", zap.Int("endpoint_count", 12))
+	rs.logger.Info("REST server endpoints registered (CLI parity + removed AI endpoints)", zap.Int("endpoint_count", 20))
 
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		rs.logger.Fatal("Failed to start server", zap.Error(err))
@@ -87,6 +100,30 @@ func (rs *RestServer) corsHandler(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// sameOriginHandler guards state-changing endpoints: no CORS headers are sent and requests
+// made by browser pages of another origin are rejected
+func (rs *RestServer) sameOriginHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rs.logger.Debug("Processing request", zap.String("method", r.Method), zap.String("path", r.URL.Path), zap.String("remote_addr", r.RemoteAddr))
+
+		if origin := r.Header.Get("Origin"); origin != "" && !sameOrigin(origin, r.Host) {
+			rs.logger.Warn("Rejected cross-origin request",
+				zap.String("path", r.URL.Path),
+				zap.String("origin", origin))
+			rs.sendError(w, "Cross-origin requests are not allowed for this endpoint", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// sameOrigin reports whether an Origin header names the host the request was sent to
+func sameOrigin(origin, host string) bool {
+	parsed, err := url.Parse(origin)
+	return err == nil && parsed.Host != "" && strings.EqualFold(parsed.Host, host)
+}
+
 // sendSuccess sends a successful API response
 func (rs *RestServer) sendSuccess(w http.ResponseWriter, data interface{}) {
 	response := models.APIResponse{
@@ -150,7 +187,15 @@ func (rs *RestServer) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 	case "PROGRAM", "PROG":
 		result, err = rs.adtClient.GetProgram(objectName)
 	case "CLASS", "CLAS":
-		result, err = rs.adtClient.GetClass(objectName)
+		// config.include selects class includes: a single include name or "all"
+		switch include := strings.ToLower(req.Config["include"]); include {
+		case "":
+			result, err = rs.adtClient.GetClass(objectName)
+		case "all":
+			result, err = rs.adtClient.GetClassIncludes(objectName, nil)
+		default:
+			result, err = rs.adtClient.GetClassIncludes(objectName, []string{include})
+		}
 	case "FUNCTION", "FUNC":
 		if len(req.Args) == 0 {
 			rs.sendError(w, "function group required in args for function modules", http.StatusBadRequest)
@@ -181,6 +226,241 @@ func (rs *RestServer) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 	rs.sendSuccess(w, result)
 }
 
+// checkObjectHandler handles syntax check requests (CLI check command equivalent)
+func (rs *RestServer) checkObjectHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ObjectType == "" || req.ObjectName == "" {
+		rs.sendError(w, "object_type and object_name are required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	functionGroup := ""
+	if len(req.Args) > 0 {
+		functionGroup = req.Args[0]
+	}
+
+	obj, err := types.ObjectReference(req.ObjectType, req.ObjectName, functionGroup)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs.logger.Info("Checking object via REST API",
+		zap.String("type", obj.Type),
+		zap.String("name", obj.Name),
+		zap.Bool("local_source", req.Source != ""))
+
+	result, err := rs.adtClient.SyntaxCheck(obj.URI, req.Source)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, result)
+}
+
+// runTestsHandler handles ABAP Unit test run requests (CLI test command equivalent)
+func (rs *RestServer) runTestsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TestRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ObjectType == "" || req.ObjectName == "" {
+		rs.sendError(w, "object_type and object_name are required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	functionGroup := ""
+	if len(req.Args) > 0 {
+		functionGroup = req.Args[0]
+	}
+
+	obj, err := types.ObjectReference(req.ObjectType, req.ObjectName, functionGroup)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs.logger.Info("Running ABAP Unit tests via REST API",
+		zap.String("type", obj.Type),
+		zap.String("name", obj.Name))
+
+	result, err := rs.adtClient.RunUnitTests([]types.ADTObject{obj})
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if strings.ToLower(req.Format) == reports.FormatJUnit {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		if err := reports.WriteUnitJUnit(w, result); err != nil {
+			rs.logger.Error("Failed to write JUnit report", zap.Error(err))
+		}
+		return
+	}
+
+	rs.sendSuccess(w, result)
+}
+
+// navigationDefinitionHandler resolves a source position to its definition (CLI nav command equivalent)
+func (rs *RestServer) navigationDefinitionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.NavigationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ObjectURI == "" && (req.ObjectType == "" || req.ObjectName == "") {
+		rs.sendError(w, "object_uri or object_type and object_name are required", http.StatusBadRequest)
+		return
+	}
+	if req.Line < 1 {
+		rs.sendError(w, "line is required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	objectURI := req.ObjectURI
+	if objectURI == "" {
+		functionGroup := ""
+		if len(req.Args) > 0 {
+			functionGroup = req.Args[0]
+		}
+		obj, err := types.ObjectReference(req.ObjectType, req.ObjectName, functionGroup)
+		if err != nil {
+			rs.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		objectURI = obj.URI
+	}
+
+	rs.logger.Info("Finding definition via REST API",
+		zap.String("uri", objectURI),
+		zap.Int("line", req.Line),
+		zap.Int("column", req.Column))
+
+	definition, err := rs.adtClient.FindDefinition(objectURI, req.Source, req.Line, req.Column)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, definition)
+}
+
+// completionHandler returns code completion proposals for an editor buffer
+func (rs *RestServer) completionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ObjectURI == "" && (req.ObjectType == "" || req.ObjectName == "") {
+		rs.sendError(w, "object_uri or object_type and object_name are required", http.StatusBadRequest)
+		return
+	}
+	if req.Source == "" {
+		rs.sendError(w, "source is required", http.StatusBadRequest)
+		return
+	}
+	if req.Line < 1 || req.Column < 0 {
+		rs.sendError(w, "line and column are required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	objectURI := req.ObjectURI
+	if objectURI == "" {
+		functionGroup := ""
+		if len(req.Args) > 0 {
+			functionGroup = req.Args[0]
+		}
+		obj, err := types.ObjectReference(req.ObjectType, req.ObjectName, functionGroup)
+		if err != nil {
+			rs.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		objectURI = obj.URI
+	}
+
+	rs.logger.Info("Code completion via REST API",
+		zap.String("uri", objectURI),
+		zap.Int("line", req.Line),
+		zap.Int("column", req.Column))
+
+	items, err := rs.adtClient.GetCompletions(objectURI, req.Source, req.Line, req.Column)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.Identifier == "" {
+		rs.sendSuccess(w, items)
+		return
+	}
+
+	for _, item := range items {
+		if !strings.EqualFold(item.Identifier, req.Identifier) {
+			continue
+		}
+		item.InsertText, err = rs.adtClient.GetCompletionInsertion(objectURI, req.Source, req.Line, req.Column, item.Identifier)
+		if err != nil {
+			rs.sendError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rs.sendSuccess(w, []types.CompletionItem{item})
+		return
+	}
+	rs.sendError(w, fmt.Sprintf("no completion proposal %s at %d:%d", req.Identifier, req.Line, req.Column), http.StatusNotFound)
+}
+
 // searchObjectsHandler handles object search requests (CLI search command equivalent)
 func (rs *RestServer) searchObjectsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -206,17 +486,34 @@ func (rs *RestServer) searchObjectsHandler(w http.ResponseWriter, r *http.Reques
 
 	pattern := req.ObjectName
 	var objectTypes []string
+	var err error
 
 	// Convert args to object types
 	for _, arg := range req.Args {
 		objectTypes = append(objectTypes, strings.ToUpper(arg))
 	}
 
+	options := types.SearchOptions{ObjectTypes: objectTypes}
+	if value, ok := req.Config["max_results"]; ok {
+		if options.MaxResults, err = strconv.Atoi(value); err != nil {
+			rs.sendError(w, "config.max_results must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if value, ok := req.Config["offset"]; ok {
+		if options.Offset, err = strconv.Atoi(value); err != nil {
+			rs.sendError(w, "config.offset must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
 	rs.logger.Info("Searching objects via REST API",
 		zap.String("pattern", pattern),
-		zap.Strings("types", objectTypes))
+		zap.Strings("types", objectTypes),
+		zap.Int("max_results", options.MaxResults),
+		zap.Int("offset", options.Offset))
 
-	results, err := rs.adtClient.SearchObjects(pattern, objectTypes)
+	results, err := rs.adtClient.SearchObjectsWithOptions(pattern, options)
 	if err != nil {
 		rs.sendError(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -271,6 +568,195 @@ func (rs *RestServer) listObjectsHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// listTransportsHandler lists the user's modifiable transport requests (CLI transport list equivalent)
+func (rs *RestServer) listTransportsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" && r.Method != "GET" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	rs.logger.Info("Listing transport requests via REST API")
+
+	transports, err := rs.adtClient.GetTransports()
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, transports)
+}
+
+// createTransportHandler creates a transport request (CLI transport create equivalent)
+func (rs *RestServer) createTransportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TransportCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Description == "" || req.Package == "" {
+		rs.sendError(w, "description and package are required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	rs.logger.Info("Creating transport request via REST API", zap.String("package", req.Package))
+
+	requestID, err := rs.adtClient.CreateTransport(req.Description, req.Package, req.ObjectURI)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, map[string]string{"request_id": requestID})
+}
+
+// checkTransportHandler checks which transport an object change needs (CLI transport check equivalent)
+func (rs *RestServer) checkTransportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TransportCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ObjectType == "" || req.ObjectName == "" {
+		rs.sendError(w, "object_type and object_name are required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	functionGroup := ""
+	if len(req.Args) > 0 {
+		functionGroup = req.Args[0]
+	}
+
+	obj, err := types.ObjectReference(req.ObjectType, req.ObjectName, functionGroup)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs.logger.Info("Checking transport via REST API",
+		zap.String("type", obj.Type),
+		zap.String("name", obj.Name))
+
+	result, err := rs.adtClient.CheckTransport(obj.URI, req.Package)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, result)
+}
+
+// assignTransportHandler records an object in a transport request or task (CLI transport assign equivalent)
+func (rs *RestServer) assignTransportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TransportAssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.RequestID == "" || req.ObjectType == "" || req.ObjectName == "" {
+		rs.sendError(w, "request_id, object_type and object_name are required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	functionGroup := ""
+	if len(req.Args) > 0 {
+		functionGroup = req.Args[0]
+	}
+
+	obj, err := types.ObjectReference(req.ObjectType, req.ObjectName, functionGroup)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs.logger.Info("Assigning object to transport via REST API",
+		zap.String("request", req.RequestID),
+		zap.String("type", obj.Type),
+		zap.String("name", obj.Name))
+
+	if err := rs.adtClient.AssignTransport(obj.URI, req.RequestID); err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, map[string]string{
+		"request_id":  strings.ToUpper(req.RequestID),
+		"object_type": obj.Type,
+		"object_name": obj.Name,
+	})
+}
+
+// releaseTransportHandler releases a transport request or task (CLI transport release equivalent)
+func (rs *RestServer) releaseTransportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		rs.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.TransportReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rs.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.RequestID == "" {
+		rs.sendError(w, "request_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if !rs.adtClient.IsAuthenticated() {
+		rs.sendError(w, "ADT client not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	rs.logger.Info("Releasing transport request via REST API", zap.String("request", req.RequestID))
+
+	result, err := rs.adtClient.ReleaseTransport(req.RequestID)
+	if err != nil {
+		rs.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs.sendSuccess(w, result)
+}
+
 // connectHandler handles connection test requests (CLI connect command equivalent)
 func (rs *RestServer) connectHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		host   string
+		want   bool
+	}{
+		{"same host and port", "http://localhost:8080", "localhost:8080", true},
+		{"case of host name", "http://LocalHost:8080", "localhost:8080", true},
+		{"other port", "http://localhost:3000", "localhost:8080", false},
+		{"other host", "https://evil.example.com", "localhost:8080", false},
+		{"host without port", "http://abaper.example.com", "abaper.example.com", true},
+		{"opaque origin", "null", "localhost:8080", false},
+		{"invalid origin", "http://%zz", "localhost:8080", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameOrigin(tt.origin, tt.host); got != tt.want {
+				t.Errorf("sameOrigin(%q, %q) = %v, want %v", tt.origin, tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameOriginHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		wantStatus int
+		wantCalled bool
+	}{
+		{"no origin", http.MethodPost, "", http.StatusOK, true},
+		{"same origin", http.MethodPost, "http://localhost:8080", http.StatusOK, true},
+		{"foreign origin", http.MethodPost, "https://evil.example.com", http.StatusForbidden, false},
+		{"other port", http.MethodPost, "http://localhost:3000", http.StatusForbidden, false},
+		{"foreign preflight", http.MethodOptions, "https://evil.example.com", http.StatusForbidden, false},
+	}
+
+	rs := NewRestServer(&Config{}, zap.NewNop(), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := rs.sameOriginHandler(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "http://localhost:8080/api/v1/transports/release", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCalled)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultSessionTimeout is how long an unused session is reused. SAP ends idle sessions
+// after rdisp/plugin_auto_logout (30 minutes by default); expired sessions are renewed anyway.
+const defaultSessionTimeout = 20 * time.Minute
+
+// unsafeFileChars are replaced in session file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// sessionTimeout returns the idle time after which a persisted session is not reused
+func sessionTimeout(config *Config) time.Duration {
+	if config.SessionTimeout > 0 {
+		return time.Duration(config.SessionTimeout) * time.Minute
+	}
+	return defaultSessionTimeout
+}
+
+// sessionPath returns the session file of a system: one per profile, or per host, client
+// and user when no profile is selected
+func sessionPath(config *Config) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := config.System
+	if name == "" {
+		sum := sha256.Sum256([]byte(credentialKey(config).String()))
+		name = hex.EncodeToString(sum[:8])
+	}
+	return filepath.Join(dir, "abaper", "sessions", unsafeFileChars.ReplaceAllString(name, "_")+".session"), nil
+}
+
+// Key derivation settings of session files, the same strength as the keyring file
+const (
+	sessionKeyIterations = 600000
+	sessionSaltSize      = 16
+)
+
+// sessionKeys caches the keys derived in this process, a session is read and saved again
+// with the same salt
+var sessionKeys = struct {
+	sync.Mutex
+	keys map[string][]byte
+}{keys: map[string][]byte{}}
+
+// sessionCipher returns AES-256-GCM under a key derived from the password of the system
+// with PBKDF2 and the salt stored in the session file. No key is stored: only who knows
+// the password can continue a saved session, and a new password makes the sessions saved
+// with the old one unreadable.
+func sessionCipher(password string, salt []byte) (cipher.AEAD, error) {
+	if password == "" {
+		return nil, fmt.Errorf("password required to derive the session key")
+	}
+
+	sum := sha256.Sum256(append(append([]byte{}, salt...), password...))
+	cacheKey := hex.EncodeToString(sum[:])
+	sessionKeys.Lock()
+	key, ok := sessionKeys.keys[cacheKey]
+	sessionKeys.Unlock()
+	if !ok {
+		derived, err := pbkdf2.Key(sha256.New, password, salt, sessionKeyIterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive session key: %w", err)
+		}
+		key = derived
+		sessionKeys.Lock()
+		sessionKeys.keys[cacheKey] = key
+		sessionKeys.Unlock()
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadSession returns the persisted session of a system, or nil if there is none that can
+// be reused. The session must belong to the same host, client and user and not be idle
+// for longer than the session timeout.
+func loadSession(config *Config, password string) *adtSession {
+	if config.NoSession {
+		return nil
+	}
+	path, err := sessionPath(config)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	// The file holds the salt, the nonce and the encrypted session
+	if len(data) < sessionSaltSize {
+		return nil
+	}
+	salt, data := data[:sessionSaltSize], data[sessionSaltSize:]
+	aead, err := sessionCipher(password, salt)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil
+	}
+	// The identity is authenticated data, a session cannot be replayed for another user
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(credentialKey(config).String()))
+	if err != nil {
+		logger.Debug("Discarding unreadable session file", zap.String("path", path))
+		return nil
+	}
+
+	var session adtSession
+	if err := json.Unmarshal(plain, &session); err != nil {
+		return nil
+	}
+	if idle := time.Since(session.LastUsed); idle > sessionTimeout(config) {
+		logger.Debug("Session expired", zap.String("path", path), zap.Duration("idle", idle))
+		return nil
+	}
+	if session.CSRFToken == "" || len(session.Cookies) == 0 {
+		return nil
+	}
+	return &session
+}
+
+// saveSession persists a session for later invocations. Failures only cost a new logon
+// next time, so they are logged and not returned.
+func saveSession(config *Config, password string, session *adtSession) {
+	if config.NoSession {
+		return
+	}
+	if err := writeSession(config, password, session); err != nil {
+		logger.Warn("Failed to save session", zap.Error(err))
+	}
+}
+
+func writeSession(config *Config, password string, session *adtSession) error {
+	path, err := sessionPath(config)
+	if err != nil {
+		return err
+	}
+	// The salt of the existing file is kept, so saving a restored session derives no new key
+	salt := make([]byte, sessionSaltSize)
+	if existing, err := os.ReadFile(path); err == nil && len(existing) >= sessionSaltSize {
+		copy(salt, existing)
+	} else if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := sessionCipher(password, salt)
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data := aead.Seal(append(salt, nonce...), nonce, plain, []byte(credentialKey(config).String()))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Other processes may save the same session at the same time
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace session file: %w", err)
+	}
+	logger.Debug("Session saved", zap.String("path", path))
+	return nil
+}
+
+// removeSession deletes the persisted session of a system
+func removeSession(config *Config) error {
+	path, err := sessionPath(config)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
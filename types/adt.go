@@ -1,6 +1,9 @@
 package types
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"fmt"
+)
 
 // ADT Response structures - shared between CLI and REST
 type ADTObject struct {
@@ -13,12 +16,19 @@ type ADTObject struct {
 	CreatedOn   string `json:"created_on" xml:"createdOn"`
 	ChangedBy   string `json:"changed_by" xml:"changedBy"`
 	ChangedOn   string `json:"changed_on" xml:"changedOn"`
+	URI         string `json:"uri,omitempty" xml:"uri,attr"`
 }
 
 type ADTPackage struct {
 	Name        string      `json:"name"`
 	Description string      `json:"description"`
 	Objects     []ADTObject `json:"objects"`
+	Subpackages []string    `json:"subpackages,omitempty"`
+
+	URI                  string `json:"uri,omitempty"`
+	ParentPackage        string `json:"parent_package,omitempty"`
+	SoftwareComponent    string `json:"software_component,omitempty"`
+	ApplicationComponent string `json:"application_component,omitempty"`
 }
 
 type ADTSourceCode struct {
@@ -27,27 +37,320 @@ type ADTSourceCode struct {
 	Source     string `json:"source"`
 	Version    string `json:"version"`
 	ETag       string `json:"etag"`
+	URI        string `json:"uri,omitempty"`        // ADT object URI, e.g. /sap/bc/adt/programs/programs/ZTEST
+	SourceURI  string `json:"source_uri,omitempty"` // source URI if not URI + /source/main (e.g. class includes)
+}
+
+// ADTClassInclude is the source of a single class include
+type ADTClassInclude struct {
+	Include string         `json:"include"` // main, definitions, implementations, testclasses or macros
+	Source  *ADTSourceCode `json:"source"`
+}
+
+// ADTLock holds the result of an ADT lock request
+type ADTLock struct {
+	LockHandle string `json:"lock_handle"`
+	CorrNr     string `json:"corr_nr,omitempty"`
+	CorrUser   string `json:"corr_user,omitempty"`
+	CorrText   string `json:"corr_text,omitempty"`
+	IsLocal    bool   `json:"is_local"`
 }
 
 type ADTSearchResult struct {
 	XMLName xml.Name    `xml:"objectReferences"` // root element
 	Objects []ADTObject `xml:"objectReference"`
-	Total   int         `xml:"total,attr"`
+	Total   int         `xml:"total,attr"` // number of hits, 0 if more exist than were fetched (HasMore)
+	Offset  int         `xml:"-"`
+	HasMore bool        `xml:"-"` // more results exist beyond the requested page
+}
+
+// CreateOptions describes where a new repository object is created
+type CreateOptions struct {
+	Description   string `json:"description,omitempty"`    // short text, defaults to the object name
+	Package       string `json:"package,omitempty"`        // package of the new object
+	FunctionGroup string `json:"function_group,omitempty"` // function group of a new function module
+	Transport     string `json:"transport,omitempty"`      // transport request for the new object
+}
+
+// SearchOptions controls object search filtering and paging
+type SearchOptions struct {
+	ObjectTypes []string `json:"object_types,omitempty"` // CLI names (program, class) or ADT codes (PROG/P)
+	MaxResults  int      `json:"max_results,omitempty"`  // page size, defaults to 100
+	Offset      int      `json:"offset,omitempty"`       // number of results to skip
 }
 
 type ADTNode struct {
 	Name        string    `json:"name"`
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
+	URI         string    `json:"uri,omitempty"`
 	Children    []ADTNode `json:"children,omitempty"`
 }
 
 type ADTTransport struct {
-	RequestID   string      `json:"request_id"`
-	Description string      `json:"description"`
-	Status      string      `json:"status"`
-	Owner       string      `json:"owner"`
-	Objects     []ADTObject `json:"objects"`
+	RequestID   string         `json:"request_id"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Owner       string         `json:"owner"`
+	Objects     []ADTObject    `json:"objects"`
+	Type        string         `json:"type,omitempty"`   // K workbench, W customizing, S/Q/R tasks
+	Target      string         `json:"target,omitempty"` // transport target system
+	Tasks       []ADTTransport `json:"tasks,omitempty"`
+}
+
+// ADTTransportCheck tells which transport request an object change needs
+type ADTTransportCheck struct {
+	Recording    bool           `json:"recording"` // changes have to be recorded in a transport request
+	Package      string         `json:"package,omitempty"`
+	DeliveryUnit string         `json:"delivery_unit,omitempty"`
+	LockedIn     string         `json:"locked_in,omitempty"` // request the object is already locked in
+	Transports   []ADTTransport `json:"transports,omitempty"`
+	Messages     []string       `json:"messages,omitempty"`
+}
+
+// ADTReleaseResult holds the outcome of releasing a transport request or task
+type ADTReleaseResult struct {
+	RequestID string             `json:"request_id"`
+	Released  bool               `json:"released"`
+	Status    string             `json:"status"`
+	Text      string             `json:"text,omitempty"`
+	Messages  []ADTSyntaxMessage `json:"messages,omitempty"`
+}
+
+// ADTUsageReference is an object that uses another object
+type ADTUsageReference struct {
+	Object           ADTObject           `json:"object"`
+	ParentURI        string              `json:"parent_uri,omitempty"`
+	UsageInformation string              `json:"usage_information,omitempty"` // e.g. gradeDirect,includeProductive
+	ObjectIdentifier string              `json:"object_identifier,omitempty"` // used to request snippets
+	Snippets         []ADTUsageSnippet   `json:"snippets,omitempty"`
+	Usages           []ADTUsageReference `json:"usages,omitempty"` // objects using this object (nested where-used)
+}
+
+// ADTUsageSnippet is a source location where an object is used
+type ADTUsageSnippet struct {
+	URI         string `json:"uri"`
+	Line        int    `json:"line,omitempty"`
+	Column      int    `json:"column,omitempty"`
+	Content     string `json:"content"`
+	Description string `json:"description,omitempty"`
+}
+
+// ADTDefinition is the defining position of a symbol
+type ADTDefinition struct {
+	Object ADTObject `json:"object"` // defining object, e.g. a class method (CLAS/OM)
+	URI    string    `json:"uri"`    // source URI of the definition
+	Line   int       `json:"line"`   // 1-based
+	Column int       `json:"column"` // 0-based, as reported by ADT
+}
+
+// CompletionItem is a code completion proposal at a source position
+type CompletionItem struct {
+	Identifier   string `json:"identifier"`
+	Kind         int    `json:"kind"`          // ADT proposal kind, e.g. keyword, variable, method
+	PrefixLength int    `json:"prefix_length"` // characters before the cursor replaced by the proposal
+	Role         int    `json:"role,omitempty"`
+	Location     int    `json:"location,omitempty"`
+	Visibility   int    `json:"visibility,omitempty"`
+	Inherited    bool   `json:"inherited,omitempty"`
+	Meta         bool   `json:"meta,omitempty"`
+	InsertText   string `json:"insert_text,omitempty"` // full insertion, e.g. a call pattern, when requested
+}
+
+// ADTFormatterSettings are the pretty printer settings of the current user
+type ADTFormatterSettings struct {
+	Indentation bool   `json:"indentation"`
+	Style       string `json:"style"` // keyword case: toLower, toUpper, keywordUpper, keywordLower, keywordAuto or none
+}
+
+// ADTVersion is a stored version of an object source
+type ADTVersion struct {
+	ID        string `json:"id"`
+	URI       string `json:"uri"` // content URI, see GetVersionSource
+	Author    string `json:"author"`
+	Date      string `json:"date"`
+	Title     string `json:"title"`
+	Transport string `json:"transport,omitempty"` // request the version was stamped with
+}
+
+// ADTStatusError is an ADT request the system answered with an error status
+type ADTStatusError struct {
+	StatusCode int    `json:"status_code"`
+	Message    string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *ADTStatusError) Error() string {
+	return e.Message
+}
+
+// Deletion error kinds
+const (
+	DeleteErrorNotFound   = "not_found"
+	DeleteErrorLocked     = "locked"
+	DeleteErrorDependency = "dependency"
+	DeleteErrorForbidden  = "forbidden"
+	DeleteErrorFailed     = "failed"
+)
+
+// ADTDeleteError describes why an object could not be deleted
+type ADTDeleteError struct {
+	URI     string              `json:"uri"`
+	Kind    string              `json:"kind"`
+	Message string              `json:"message"`
+	Usages  []ADTUsageReference `json:"usages,omitempty"` // objects still using the object (dependency errors)
+}
+
+// Error implements the error interface
+func (e *ADTDeleteError) Error() string {
+	if len(e.Usages) > 0 {
+		return fmt.Sprintf("cannot delete %s (%s): %s - used by %d object(s)", e.URI, e.Kind, e.Message, len(e.Usages))
+	}
+	return fmt.Sprintf("cannot delete %s (%s): %s", e.URI, e.Kind, e.Message)
+}
+
+// ADTActivationMessage is a single message from an activation run
+type ADTActivationMessage struct {
+	ObjectDescription string `json:"object_description"`
+	Type              string `json:"type"` // E, W or I
+	Severity          string `json:"severity"`
+	URI               string `json:"uri,omitempty"`
+	Line              int    `json:"line,omitempty"`
+	Column            int    `json:"column,omitempty"`
+	Text              string `json:"text"`
+}
+
+// ADTActivationResult holds the outcome of an activation request
+type ADTActivationResult struct {
+	Success  bool                   `json:"success"`
+	Messages []ADTActivationMessage `json:"messages"`
+	Inactive []ADTObject            `json:"inactive,omitempty"` // dependent objects that are still inactive
+}
+
+// ADTInactiveObject is an entry of the user's inactive object list
+type ADTInactiveObject struct {
+	Object    ADTObject `json:"object"`
+	User      string    `json:"user"`
+	Deleted   bool      `json:"deleted"`
+	Transport string    `json:"transport,omitempty"`
+}
+
+// ADTSyntaxMessage is a single finding of a syntax check
+type ADTSyntaxMessage struct {
+	URI      string `json:"uri,omitempty"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Type     string `json:"type"` // E, W or I
+	Severity string `json:"severity"`
+	Text     string `json:"text"`
+	Category string `json:"category,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+// ADTSyntaxCheckResult holds the findings of a syntax check run
+type ADTSyntaxCheckResult struct {
+	ObjectURI string             `json:"object_uri"`
+	Status    string             `json:"status"`
+	HasErrors bool               `json:"has_errors"`
+	Messages  []ADTSyntaxMessage `json:"messages"`
+}
+
+// ADTATCFinding is a single ATC finding
+type ADTATCFinding struct {
+	ObjectURI    string `json:"object_uri"`
+	ObjectName   string `json:"object_name"`
+	ObjectType   string `json:"object_type"`
+	Package      string `json:"package,omitempty"`
+	Priority     int    `json:"priority"` // 1 = error, 2 = warning, 3 = info
+	CheckID      string `json:"check_id"`
+	CheckTitle   string `json:"check_title"`
+	MessageID    string `json:"message_id"`
+	MessageTitle string `json:"message_title"`
+	URI          string `json:"uri,omitempty"` // source location without position
+	Line         int    `json:"line,omitempty"`
+	Column       int    `json:"column,omitempty"`
+}
+
+// ADTATCResult holds the findings of an ATC worklist run
+type ADTATCResult struct {
+	WorklistID string          `json:"worklist_id"`
+	Variant    string          `json:"variant"`
+	Timestamp  string          `json:"timestamp"`
+	Findings   []ADTATCFinding `json:"findings"`
+}
+
+// ADTUnitStackEntry is a single entry of an ABAP Unit alert stack
+type ADTUnitStackEntry struct {
+	URI         string `json:"uri"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ADTUnitAlert is a failed assertion, exception or warning raised by ABAP Unit
+type ADTUnitAlert struct {
+	Kind     string              `json:"kind"`     // failedAssertion, exception, warning, ...
+	Severity string              `json:"severity"` // critical, fatal, tolerable
+	Title    string              `json:"title"`
+	Details  []string            `json:"details,omitempty"`
+	Stack    []ADTUnitStackEntry `json:"stack,omitempty"`
+}
+
+// ABAP Unit outcomes of a test method, class or program
+const (
+	UnitPassed = "passed"
+	UnitFailed = "failed"
+	UnitError  = "error"
+)
+
+// UnitOutcome classifies alerts: the first critical or fatal alert fails the test, as an
+// error if it is an exception. The deciding alert is returned with the outcome.
+func UnitOutcome(alerts []ADTUnitAlert) (string, *ADTUnitAlert) {
+	for i, alert := range alerts {
+		if alert.Severity != "critical" && alert.Severity != "fatal" {
+			continue
+		}
+		if alert.Kind == "exception" {
+			return UnitError, &alerts[i]
+		}
+		return UnitFailed, &alerts[i]
+	}
+	return UnitPassed, nil
+}
+
+// ADTUnitMethod is an executed ABAP Unit test method
+type ADTUnitMethod struct {
+	Name          string         `json:"name"`
+	URI           string         `json:"uri"`
+	ExecutionTime float64        `json:"execution_time"` // seconds
+	Alerts        []ADTUnitAlert `json:"alerts,omitempty"`
+}
+
+// ADTUnitClass is an ABAP Unit test class
+type ADTUnitClass struct {
+	Name             string          `json:"name"`
+	URI              string          `json:"uri"`
+	DurationCategory string          `json:"duration_category,omitempty"`
+	RiskLevel        string          `json:"risk_level,omitempty"`
+	Methods          []ADTUnitMethod `json:"methods"`
+	Alerts           []ADTUnitAlert  `json:"alerts,omitempty"`
+}
+
+// ADTUnitProgram is a program or class containing ABAP Unit test classes
+type ADTUnitProgram struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"`
+	URI     string         `json:"uri"`
+	Classes []ADTUnitClass `json:"classes"`
+	Alerts  []ADTUnitAlert `json:"alerts,omitempty"`
+}
+
+// ADTUnitRunResult holds the result of an ABAP Unit test run
+type ADTUnitRunResult struct {
+	Programs []ADTUnitProgram `json:"programs"`
+	Tests    int              `json:"tests"`
+	Failures int              `json:"failures"`
+	Errors   int              `json:"errors"`
 }
 
 // ADT Configuration
@@ -58,6 +361,7 @@ type ADTConfig struct {
 	Password        string `json:"password"`
 	Language        string `json:"language"`
 	AllowSelfSigned bool   `json:"allow_self_signed"`
+	CAFile          string `json:"ca_file,omitempty"`
 	ConnectTimeout  int    `json:"connect_timeout"`
 	RequestTimeout  int    `json:"request_timeout"`
 	Debug           bool   `json:"debug"`
@@ -108,17 +412,26 @@ type ADTClient interface {
 	// Core object retrieval methods
 	GetProgram(name string) (*ADTSourceCode, error)
 	GetClass(name string) (*ADTSourceCode, error)
+	GetClassIncludes(name string, includes []string) ([]ADTClassInclude, error)
 	GetFunction(name, functionGroup string) (*ADTSourceCode, error)
 	GetInclude(name string) (*ADTSourceCode, error)
 	GetInterface(name string) (*ADTSourceCode, error)
 	GetStructure(name string) (*ADTSourceCode, error)
 	GetTable(name string) (*ADTSourceCode, error)
 	GetFunctionGroup(name string) (*ADTSourceCode, error)
+	GetSourceByURI(sourceURI string) (*ADTSourceCode, error)
+	GetVersions(objectURI string) ([]ADTVersion, error)
+	GetVersionSource(versionURI string) (string, error)
 
 	// Package and search operations
 	GetPackageContents(name string) (*ADTPackage, error)
+	GetPackageTree(name string) (*ADTNode, error)
+	WhereUsed(objectURI string) ([]ADTUsageReference, error)
+	GetFunctionGroupContents(name string) ([]ADTObject, error)
 	SearchObjects(pattern string, objectTypes []string) (*ADTSearchResult, error)
+	SearchObjectsWithOptions(pattern string, options SearchOptions) (*ADTSearchResult, error)
 	ListPackages(pattern string) ([]ADTPackage, error)
+	GetPackageInfo(name string) (*ADTPackage, error)
 
 	// Connection and session management
 	TestConnection() error
@@ -131,5 +444,29 @@ type ADTClient interface {
 	GetTransaction(transactionName string) (*ADTTransactionInfo, error)
 	GetTableContents(tableName string, maxRows int) (*ADTTableData, error)
 	GetTransports() ([]ADTTransport, error)
-	CreateProgram(name, description, source string) error
+	CreateTransport(description, packageName, objectURI string) (string, error)
+	CheckTransport(objectURI, packageName string) (*ADTTransportCheck, error)
+	AssignTransport(objectURI, requestID string) error
+	ReleaseTransport(requestID string) (*ADTReleaseResult, error)
+
+	// Write operations
+	CreateObject(kind, name string, options CreateOptions) error
+	LockObject(objectURI string) (*ADTLock, error)
+	UnlockObject(objectURI, lockHandle string) error
+	UpdateSource(source *ADTSourceCode, transport string) (*ADTSourceCode, error)
+	DeleteObject(objectURI, transport string) error
+
+	// Activation
+	Activate(objects []ADTObject) (*ADTActivationResult, error)
+	GetInactiveObjects() ([]ADTInactiveObject, error)
+
+	// Quality checks
+	SyntaxCheck(objectURI, source string) (*ADTSyntaxCheckResult, error)
+	FindDefinition(objectURI, source string, line, column int) (*ADTDefinition, error)
+	GetCompletions(objectURI, source string, line, column int) ([]CompletionItem, error)
+	GetCompletionInsertion(objectURI, source string, line, column int, identifier string) (string, error)
+	Format(source string) (string, error)
+	GetFormatterSettings() (*ADTFormatterSettings, error)
+	RunATC(objects []ADTObject, variant string, maxVerdicts int) (*ADTATCResult, error)
+	RunUnitTests(objects []ADTObject) (*ADTUnitRunResult, error)
 }
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ADT object type codes
+const (
+	ObjectTypeProgram       = "PROG/P"
+	ObjectTypeInclude       = "PROG/I"
+	ObjectTypeClass         = "CLAS/OC"
+	ObjectTypeInterface     = "INTF/OI"
+	ObjectTypeFunctionGroup = "FUGR/F"
+	ObjectTypeFunction      = "FUGR/FF"
+	ObjectTypeFunctionInc   = "FUGR/I"
+	ObjectTypeTable         = "TABL/DT"
+	ObjectTypeStructure     = "TABL/DS"
+	ObjectTypePackage       = "DEVC/K"
+)
+
+// Class include names
+const (
+	ClassIncludeMain            = "main"
+	ClassIncludeDefinitions     = "definitions"
+	ClassIncludeImplementations = "implementations"
+	ClassIncludeTestClasses     = "testclasses"
+	ClassIncludeMacros          = "macros"
+)
+
+// ClassIncludes lists all class includes in display order
+var ClassIncludes = []string{
+	ClassIncludeMain,
+	ClassIncludeDefinitions,
+	ClassIncludeImplementations,
+	ClassIncludeTestClasses,
+	ClassIncludeMacros,
+}
+
+// ObjectTypeCode maps a CLI/REST object type to its ADT type code.
+// Values that already are type codes (contain a slash) are returned unchanged.
+func ObjectTypeCode(objectType string) (string, error) {
+	objectType = strings.ToUpper(strings.TrimSpace(objectType))
+	if strings.Contains(objectType, "/") {
+		return objectType, nil
+	}
+
+	switch objectType {
+	case "PROGRAM", "PROG", "REPORT":
+		return ObjectTypeProgram, nil
+	case "INCLUDE", "INCL":
+		return ObjectTypeInclude, nil
+	case "CLASS", "CLAS":
+		return ObjectTypeClass, nil
+	case "INTERFACE", "INTF":
+		return ObjectTypeInterface, nil
+	case "FUNCTION_GROUP", "FUNCTIONGROUP", "FUGR":
+		return ObjectTypeFunctionGroup, nil
+	case "FUNCTION", "FUNC":
+		return ObjectTypeFunction, nil
+	case "TABLE", "TABL":
+		return ObjectTypeTable, nil
+	case "STRUCTURE", "STRU":
+		return ObjectTypeStructure, nil
+	case "PACKAGE", "PACK", "DEVC":
+		return ObjectTypePackage, nil
+	default:
+		return "", fmt.Errorf("unsupported object type: %s", objectType)
+	}
+}
+
+// ObjectReference builds an ADTObject with type code and URI for a CLI/REST object type.
+// parent is the function group for function modules and ignored otherwise.
+func ObjectReference(objectType, name, parent string) (ADTObject, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	parent = strings.ToUpper(strings.TrimSpace(parent))
+
+	if name == "" {
+		return ADTObject{}, fmt.Errorf("object name required")
+	}
+
+	obj := ADTObject{Name: name}
+
+	switch strings.ToUpper(objectType) {
+	case "PROGRAM", "PROG", "REPORT", ObjectTypeProgram:
+		obj.Type = ObjectTypeProgram
+		obj.URI = "/sap/bc/adt/programs/programs/" + name
+	case "INCLUDE", "INCL", ObjectTypeInclude:
+		obj.Type = ObjectTypeInclude
+		obj.URI = "/sap/bc/adt/programs/includes/" + name
+	case "CLASS", "CLAS", ObjectTypeClass:
+		obj.Type = ObjectTypeClass
+		obj.URI = "/sap/bc/adt/oo/classes/" + name
+	case "INTERFACE", "INTF", ObjectTypeInterface:
+		obj.Type = ObjectTypeInterface
+		obj.URI = "/sap/bc/adt/oo/interfaces/" + name
+	case "FUNCTION_GROUP", "FUNCTIONGROUP", "FUGR", ObjectTypeFunctionGroup:
+		obj.Type = ObjectTypeFunctionGroup
+		obj.URI = "/sap/bc/adt/functions/groups/" + name
+	case "FUNCTION", "FUNC", ObjectTypeFunction:
+		if parent == "" {
+			return ADTObject{}, fmt.Errorf("function group required for function module %s", name)
+		}
+		obj.Type = ObjectTypeFunction
+		obj.URI = "/sap/bc/adt/functions/groups/" + parent + "/fmodules/" + name
+	case "TABLE", "TABL", ObjectTypeTable:
+		obj.Type = ObjectTypeTable
+		obj.URI = "/sap/bc/adt/ddic/tables/" + name
+	case "STRUCTURE", "STRU", ObjectTypeStructure:
+		obj.Type = ObjectTypeStructure
+		obj.URI = "/sap/bc/adt/ddic/structures/" + name
+	case "PACKAGE", "PACK", "DEVC", ObjectTypePackage:
+		obj.Type = ObjectTypePackage
+		obj.URI = "/sap/bc/adt/packages/" + name
+	default:
+		return ADTObject{}, fmt.Errorf("unsupported object type: %s", objectType)
+	}
+
+	return obj, nil
+}
+
+// ClassIncludeURI returns the source URI of a class include
+func ClassIncludeURI(className, include string) (string, error) {
+	className = strings.ToUpper(strings.TrimSpace(className))
+	include = strings.ToLower(strings.TrimSpace(include))
+
+	switch include {
+	case ClassIncludeMain, "":
+		return "/sap/bc/adt/oo/classes/" + className + "/source/main", nil
+	case ClassIncludeDefinitions, ClassIncludeImplementations, ClassIncludeTestClasses, ClassIncludeMacros:
+		return "/sap/bc/adt/oo/classes/" + className + "/includes/" + include, nil
+	default:
+		return "", fmt.Errorf("unknown class include: %s (use %s)", include, strings.Join(ClassIncludes, ", "))
+	}
+}